@@ -0,0 +1,36 @@
+// Servidor falso dos fornecedores de IA para desenvolvimento offline.
+//
+//	go run ./cmd/mockai -addr :8787 -script mockai.example.json
+//
+// Na API, aponte todos os fornecedores para ele com AI_MOCK_URL=http://localhost:8787
+// (ou um por um com AI_<PROVEDOR>_BASE_URL=http://localhost:8787/<provedor>/...).
+package main
+
+import (
+	"flag"
+	"lingobotAPI-GO/mockai"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8787", "endereço do servidor falso")
+	scriptPath := flag.String("script", "", "arquivo JSON com o roteiro das respostas (vazio = sempre sucesso)")
+	flag.Parse()
+
+	var script mockai.Script
+	if *scriptPath != "" {
+		var err error
+		script, err = mockai.LoadScript(*scriptPath)
+		if err != nil {
+			log.Fatalf("❌ Erro ao ler roteiro: %v", err)
+		}
+	}
+
+	server := mockai.New(script)
+
+	log.Printf("🤖 Mock dos fornecedores de IA em %s (%v)", *addr, mockai.Providers)
+	if err := http.ListenAndServe(*addr, server.Handler()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,436 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// AIProviderConfig - Configuração de um provedor de IA
+type AIProviderConfig struct {
+	Enabled bool               `json:"enabled"`
+	Timeout time.Duration      `json:"timeout"`  // 0 = sem timeout extra (vale o do cliente HTTP)
+	BaseURL string             `json:"base_url"` // vazio = URL oficial do fornecedor
+	Models  map[string]AIModel `json:"models"`   // apelido -> modelo(s); completa o catálogo padrão
+}
+
+// AIModel - Modelo(s) de um apelido do catálogo. Com mais de um ID o provedor
+// tenta em ordem (ex.: modelos gratuitos do OpenRouter).
+type AIModel struct {
+	IDs     []string `json:"ids"`
+	Premium bool     `json:"premium"` // liberado só para planos premium
+}
+
+// AIConfig - Configuração da cadeia de fallback das IAs
+type AIConfig struct {
+	Order        []string                    `json:"order"`         // vazio = ordem padrão do registro
+	DefaultModel string                      `json:"default_model"` // apelido usado quando a requisição não escolhe
+	MockURL      string                      `json:"mock_url"`      // servidor falso (cmd/mockai) para todos os fornecedores
+	Providers    map[string]AIProviderConfig `json:"providers"`
+}
+
+// defaultBaseURLs são as URLs oficiais de cada fornecedor (IA, TTS e transcrição)
+var defaultBaseURLs = map[string]string{
+	"gemini":     "https://generativelanguage.googleapis.com/v1beta",
+	"mistral":    "https://api.mistral.ai/v1",
+	"cohere":     "https://api.cohere.ai/v1",
+	"groq":       "https://api.groq.com/openai/v1",
+	"openrouter": "https://openrouter.ai/api/v1",
+	"elevenlabs": "https://api.elevenlabs.io/v1",
+	"assemblyai": "https://api.assemblyai.com/v2",
+}
+
+// Provider retorna a configuração de um provedor (habilitado e sem timeout por padrão)
+func (c *AIConfig) Provider(name string) AIProviderConfig {
+	if pc, ok := c.Providers[name]; ok {
+		return pc
+	}
+	return AIProviderConfig{Enabled: true}
+}
+
+// defaultModelAlias é o apelido usado quando nem a requisição nem a configuração escolhem
+const defaultModelAlias = "fast"
+
+// defaultModels é o catálogo padrão de modelos por provedor.
+// "fast" é o modelo de sempre; "cheap" o mais barato; "smart" o maior, só para premium.
+var defaultModels = map[string]map[string]AIModel{
+	"gemini": {
+		"fast":  {IDs: []string{"gemini-2.0-flash"}},
+		"cheap": {IDs: []string{"gemini-2.0-flash-lite"}},
+		"smart": {IDs: []string{"gemini-2.5-pro"}, Premium: true},
+	},
+	"mistral": {
+		"fast":  {IDs: []string{"mistral-tiny"}},
+		"cheap": {IDs: []string{"mistral-tiny"}},
+		"smart": {IDs: []string{"mistral-large-latest"}, Premium: true},
+	},
+	"cohere": {
+		"fast":  {IDs: []string{"command-r"}},
+		"cheap": {IDs: []string{"command-r7b-12-2024"}},
+		"smart": {IDs: []string{"command-r-plus"}, Premium: true},
+	},
+	"groq": {
+		"fast":  {IDs: []string{"meta-llama/llama-4-scout-17b-16e-instruct"}},
+		"cheap": {IDs: []string{"llama-3.1-8b-instant"}},
+		"smart": {IDs: []string{"llama-3.3-70b-versatile"}, Premium: true},
+	},
+	"openrouter": {
+		"fast": {IDs: []string{
+			"qwen/qwen3-235b-a22b-07-25:free",
+			"meta-llama/llama-3.1-8b-instruct:free",
+			"microsoft/phi-3-mini-128k-instruct:free",
+			"google/gemma-2-9b-it:free",
+		}},
+		"cheap": {IDs: []string{
+			"meta-llama/llama-3.1-8b-instruct:free",
+			"google/gemma-2-9b-it:free",
+		}},
+		"smart": {IDs: []string{"qwen/qwen3-235b-a22b-07-25", "meta-llama/llama-3.3-70b-instruct"}, Premium: true},
+	},
+}
+
+// ModelAlias retorna o apelido efetivo: o pedido ou, se vazio, o padrão da configuração
+func (c *AIConfig) ModelAlias(alias string) string {
+	if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+		return alias
+	}
+	if c.DefaultModel != "" {
+		return c.DefaultModel
+	}
+	return defaultModelAlias
+}
+
+// Model retorna o modelo de um apelido para o provedor (configuração e depois catálogo padrão).
+// Apelido vazio usa o padrão; ok é false se o provedor não tiver o apelido.
+func (c *AIConfig) Model(provider, alias string) (AIModel, bool) {
+	alias = c.ModelAlias(alias)
+
+	model, ok := c.Provider(provider).Models[alias]
+	if ok && len(model.IDs) > 0 {
+		return model, true
+	}
+
+	model, ok = defaultModels[provider][alias]
+	return model, ok
+}
+
+// ModelAliases lista os apelidos de modelo do provedor (catálogo padrão e configuração), em ordem alfabética
+func (c *AIConfig) ModelAliases(provider string) []string {
+	seen := map[string]bool{}
+	for alias := range defaultModels[provider] {
+		seen[alias] = true
+	}
+	for alias, model := range c.Provider(provider).Models {
+		if len(model.IDs) > 0 {
+			seen[alias] = true
+		}
+	}
+
+	aliases := make([]string, 0, len(seen))
+	for alias := range seen {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// BaseURL retorna a URL base de um fornecedor, sem barra no final.
+// Vale a URL configurada para o fornecedor; sem ela, com MockURL definido, o caminho
+// oficial é servido pelo servidor falso em <MockURL>/<fornecedor> (ex.: /mistral/v1).
+func (c *AIConfig) BaseURL(name string) string {
+	if baseURL := c.Provider(name).BaseURL; baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+
+	baseURL := defaultBaseURLs[name]
+	if c.MockURL == "" {
+		return baseURL
+	}
+
+	path := ""
+	if u, err := url.Parse(baseURL); err == nil {
+		path = u.Path
+	}
+	return strings.TrimSuffix(c.MockURL, "/") + "/" + name + path
+}
+
+// aiConfigFile é o formato do arquivo JSON (timeouts como "15s", "2m")
+type aiConfigFile struct {
+	Order        []string `json:"order"`
+	DefaultModel string   `json:"default_model"`
+	MockURL      string   `json:"mock_url"`
+	Providers    map[string]struct {
+		Enabled *bool              `json:"enabled"`
+		Timeout string             `json:"timeout"`
+		BaseURL string             `json:"base_url"`
+		Models  map[string]AIModel `json:"models"`
+	} `json:"providers"`
+}
+
+// Intervalo mínimo entre verificações do AI_CONFIG_FILE (evita um stat por requisição)
+const aiConfigReloadInterval = 5 * time.Second
+
+var (
+	aiConfigMu        sync.Mutex
+	aiConfig          *AIConfig
+	aiConfigModTime   time.Time
+	aiConfigCheckedAt time.Time
+)
+
+// GetAIConfig retorna a configuração atual das IAs.
+//
+// A configuração vem de variáveis de ambiente (AI_PROVIDER_ORDER, AI_MOCK_URL, AI_DEFAULT_MODEL,
+// AI_<PROVEDOR>_ENABLED, AI_<PROVEDOR>_TIMEOUT, AI_<PROVEDOR>_BASE_URL e
+// AI_<PROVEDOR>_MODEL_<APELIDO>=id1,id2) e, opcionalmente, de um arquivo
+// JSON apontado por AI_CONFIG_FILE. O arquivo é relido quando muda (verificado a cada
+// aiConfigReloadInterval), então o tráfego pode ser redirecionado sem redeploy; por isso
+// ele tem precedência sobre o ambiente.
+func GetAIConfig() *AIConfig {
+	aiConfigMu.Lock()
+	defer aiConfigMu.Unlock()
+
+	now := time.Now()
+	if aiConfig != nil && now.Sub(aiConfigCheckedAt) < aiConfigReloadInterval {
+		return aiConfig
+	}
+	aiConfigCheckedAt = now
+
+	path := os.Getenv("AI_CONFIG_FILE")
+
+	var modTime time.Time
+	if path != "" {
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime()
+		}
+	}
+
+	if aiConfig != nil && modTime.Equal(aiConfigModTime) {
+		return aiConfig
+	}
+
+	cfg := loadAIConfigFromEnv()
+	if path != "" && !modTime.IsZero() {
+		if err := applyAIConfigFile(cfg, path); err != nil {
+			log.Printf("⚠️  Aviso: erro ao ler %s, usando configuração do ambiente: %v", path, err)
+		}
+	}
+
+	aiConfig = cfg
+	aiConfigModTime = modTime
+	return aiConfig
+}
+
+// loadAIConfigFromEnv monta a configuração a partir das variáveis de ambiente
+func loadAIConfigFromEnv() *AIConfig {
+	cfg := &AIConfig{Providers: map[string]AIProviderConfig{}}
+
+	if order := os.Getenv("AI_PROVIDER_ORDER"); order != "" {
+		cfg.Order = splitList(order)
+	}
+	cfg.MockURL = os.Getenv("AI_MOCK_URL")
+	cfg.DefaultModel = strings.ToLower(strings.TrimSpace(os.Getenv("AI_DEFAULT_MODEL")))
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if !strings.HasPrefix(key, "AI_") {
+			continue
+		}
+
+		var name, field, alias string
+		switch {
+		case key == "AI_MOCK_URL", key == "AI_DEFAULT_MODEL":
+			continue
+		case strings.Contains(key, "_MODEL_"):
+			name, alias, _ = strings.Cut(strings.TrimPrefix(key, "AI_"), "_MODEL_")
+			field, alias = "model", strings.ToLower(alias)
+		case strings.HasSuffix(key, "_BASE_URL"):
+			name, field = strings.TrimSuffix(strings.TrimPrefix(key, "AI_"), "_BASE_URL"), "base_url"
+		case strings.HasSuffix(key, "_ENABLED"):
+			name, field = strings.TrimSuffix(strings.TrimPrefix(key, "AI_"), "_ENABLED"), "enabled"
+		case strings.HasSuffix(key, "_TIMEOUT"):
+			name, field = strings.TrimSuffix(strings.TrimPrefix(key, "AI_"), "_TIMEOUT"), "timeout"
+		default:
+			continue
+		}
+		name = strings.ToLower(name)
+
+		pc := cfg.Provider(name)
+		switch field {
+		case "enabled":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Printf("⚠️  Aviso: %s inválido: %q", key, value)
+				continue
+			}
+			pc.Enabled = enabled
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil {
+				log.Printf("⚠️  Aviso: %s inválido: %q", key, value)
+				continue
+			}
+			pc.Timeout = timeout
+		case "base_url":
+			pc.BaseURL = value
+		case "model":
+			// Mantém a marcação premium do catálogo padrão; só troca os IDs
+			model := defaultModels[name][alias]
+			model.IDs = strings.Split(strings.ReplaceAll(value, " ", ""), ",")
+			if pc.Models == nil {
+				pc.Models = map[string]AIModel{}
+			}
+			pc.Models[alias] = model
+		}
+		cfg.Providers[name] = pc
+	}
+
+	return cfg
+}
+
+// applyAIConfigFile sobrescreve a configuração com os valores do arquivo JSON
+func applyAIConfigFile(cfg *AIConfig, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file aiConfigFile
+	if err := sonic.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	if len(file.Order) > 0 {
+		cfg.Order = splitList(strings.Join(file.Order, ","))
+	}
+	if file.MockURL != "" {
+		cfg.MockURL = file.MockURL
+	}
+	if file.DefaultModel != "" {
+		cfg.DefaultModel = strings.ToLower(file.DefaultModel)
+	}
+
+	for name, fp := range file.Providers {
+		pc := cfg.Provider(name)
+		if fp.Enabled != nil {
+			pc.Enabled = *fp.Enabled
+		}
+		if fp.Timeout != "" {
+			timeout, err := time.ParseDuration(fp.Timeout)
+			if err != nil {
+				return fmt.Errorf("timeout inválido para %s: %v", name, err)
+			}
+			pc.Timeout = timeout
+		}
+		if fp.BaseURL != "" {
+			pc.BaseURL = fp.BaseURL
+		}
+		for alias, model := range fp.Models {
+			if pc.Models == nil {
+				pc.Models = map[string]AIModel{}
+			}
+			pc.Models[strings.ToLower(alias)] = model
+		}
+		cfg.Providers[name] = pc
+	}
+
+	return nil
+}
+
+// splitList separa uma lista "a, b,c" em itens sem espaços
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AICacheConfig - Configuração do cache de respostas das IAs
+type AICacheConfig struct {
+	Backend    string        // memory (padrão), postgres ou off
+	TTL        time.Duration // validade de cada resposta
+	MaxEntries int           // limite de respostas guardadas
+}
+
+// GetAICacheConfig lê a configuração do cache (AI_CACHE, AI_CACHE_TTL, AI_CACHE_MAX_ENTRIES)
+func GetAICacheConfig() AICacheConfig {
+	cfg := AICacheConfig{
+		Backend:    "memory",
+		TTL:        24 * time.Hour,
+		MaxEntries: 1000,
+	}
+
+	if backend := strings.ToLower(strings.TrimSpace(os.Getenv("AI_CACHE"))); backend != "" {
+		cfg.Backend = backend
+	}
+
+	if value := os.Getenv("AI_CACHE_TTL"); value != "" {
+		if ttl, err := time.ParseDuration(value); err == nil && ttl > 0 {
+			cfg.TTL = ttl
+		} else {
+			log.Printf("⚠️  Aviso: AI_CACHE_TTL inválido: %q", value)
+		}
+	}
+
+	if value := os.Getenv("AI_CACHE_MAX_ENTRIES"); value != "" {
+		if maxEntries, err := strconv.Atoi(value); err == nil && maxEntries > 0 {
+			cfg.MaxEntries = maxEntries
+		} else {
+			log.Printf("⚠️  Aviso: AI_CACHE_MAX_ENTRIES inválido: %q", value)
+		}
+	}
+
+	return cfg
+}
+
+// AIRetryConfig - Política de novas tentativas das chamadas aos fornecedores
+type AIRetryConfig struct {
+	MaxAttempts int           // total de tentativas, incluindo a primeira (1 = sem retry)
+	BaseDelay   time.Duration // espera antes da segunda tentativa; dobra a cada falha
+	MaxDelay    time.Duration // limite de cada espera (inclusive do Retry-After)
+}
+
+// GetAIRetryConfig lê a política de retry (AI_RETRY_MAX_ATTEMPTS, AI_RETRY_BASE_DELAY, AI_RETRY_MAX_DELAY)
+func GetAIRetryConfig() AIRetryConfig {
+	cfg := AIRetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+
+	if value := os.Getenv("AI_RETRY_MAX_ATTEMPTS"); value != "" {
+		if attempts, err := strconv.Atoi(value); err == nil && attempts > 0 {
+			cfg.MaxAttempts = attempts
+		} else {
+			log.Printf("⚠️  Aviso: AI_RETRY_MAX_ATTEMPTS inválido: %q", value)
+		}
+	}
+
+	if value := os.Getenv("AI_RETRY_BASE_DELAY"); value != "" {
+		if delay, err := time.ParseDuration(value); err == nil && delay >= 0 {
+			cfg.BaseDelay = delay
+		} else {
+			log.Printf("⚠️  Aviso: AI_RETRY_BASE_DELAY inválido: %q", value)
+		}
+	}
+
+	if value := os.Getenv("AI_RETRY_MAX_DELAY"); value != "" {
+		if delay, err := time.ParseDuration(value); err == nil && delay >= 0 {
+			cfg.MaxDelay = delay
+		} else {
+			log.Printf("⚠️  Aviso: AI_RETRY_MAX_DELAY inválido: %q", value)
+		}
+	}
+
+	return cfg
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CSRFHeader é o header em que o cliente repete o claim csrf do token
+const CSRFHeader = "X-CSRF-Token"
+
+// Cookies da sessão por cookie (cliente web). Os de CSRF são legíveis pelo JavaScript, que
+// repete o valor no header X-CSRF-Token (double submit).
+const (
+	AccessTokenCookie  = "access_token_cookie"
+	RefreshTokenCookie = "refresh_token_cookie"
+	CSRFAccessCookie   = "csrf_access_token"
+	CSRFRefreshCookie  = "csrf_refresh_token"
+)
+
+// AuthConfig - Configuração da autenticação JWT
+type AuthConfig struct {
+	RequireCSRF    bool          // exige X-CSRF-Token igual ao claim csrf em métodos que alteram dados
+	CookieSessions bool          // permite login com tokens em cookies HttpOnly ("cookie": true)
+	CookieSecure   bool          // cookies só em HTTPS (desligue apenas em desenvolvimento)
+	CookieSameSite http.SameSite // lax (padrão), strict ou none
+	CookieDomain   string        // vazio = domínio da API
+}
+
+// GetAuthConfig lê a configuração da autenticação (AUTH_CSRF_REQUIRED, AUTH_COOKIES,
+// AUTH_COOKIE_SECURE, AUTH_COOKIE_SAMESITE, AUTH_COOKIE_DOMAIN)
+func GetAuthConfig() AuthConfig {
+	cfg := AuthConfig{
+		CookieSecure:   true,
+		CookieSameSite: http.SameSiteLaxMode,
+		CookieDomain:   os.Getenv("AUTH_COOKIE_DOMAIN"),
+	}
+
+	cfg.RequireCSRF = envBool("AUTH_CSRF_REQUIRED", cfg.RequireCSRF)
+	cfg.CookieSessions = envBool("AUTH_COOKIES", cfg.CookieSessions)
+	cfg.CookieSecure = envBool("AUTH_COOKIE_SECURE", cfg.CookieSecure)
+
+	switch value := strings.ToLower(strings.TrimSpace(os.Getenv("AUTH_COOKIE_SAMESITE"))); value {
+	case "", "lax":
+	case "strict":
+		cfg.CookieSameSite = http.SameSiteStrictMode
+	case "none":
+		cfg.CookieSameSite = http.SameSiteNoneMode
+		if !cfg.CookieSecure {
+			log.Printf("⚠️  Aviso: AUTH_COOKIE_SAMESITE=none exige cookies Secure; os navegadores vão recusá-los")
+		}
+	default:
+		log.Printf("⚠️  Aviso: AUTH_COOKIE_SAMESITE inválido: %q", value)
+	}
+
+	return cfg
+}
+
+// envBool lê uma variável booleana, mantendo o padrão se ela estiver vazia ou inválida
+func envBool(name string, fallback bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("⚠️  Aviso: %s inválido: %q", name, value)
+		return fallback
+	}
+	return parsed
+}
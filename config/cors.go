@@ -15,7 +15,8 @@ func SetupCORS(router *gin.Engine) {
 			"https://localhost:8100", // (caso use HTTPS no futuro)
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-CSRF-Token"},
+		ExposeHeaders:    []string{"X-AI-Cache"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
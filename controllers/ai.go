@@ -1,11 +1,12 @@
 package controllers
 
 import (
+	"errors"
 	"lingobotAPI-GO/models"
 	"lingobotAPI-GO/services"
 	"lingobotAPI-GO/utils"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,18 +20,25 @@ func AIGemini(c *gin.Context) {
 		return
 	}
 
-	response, err := services.CallAIWithFallback(req.Text, req.Mistral, req.Cohere, req.Groq)
+	completion, ok := completionRequest(c, req)
+	if !ok {
+		return
+	}
+
+	response, cached, err := services.CallAIWithCache(c.Request.Context(), completion, req.Providers)
 	if err != nil {
-		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
 		return
 	}
 
+	setCacheHeader(c, cached)
+
 	// Retorna texto puro
-	c.String(http.StatusOK, response)
+	c.String(http.StatusOK, response.Text)
 }
 
-// AICohere endpoint específico para Cohere
-func AICohere(c *gin.Context) {
+// AIProvider endpoint específico para um provedor (/ai/:provider)
+func AIProvider(c *gin.Context) {
 	var req models.AIRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -38,17 +46,26 @@ func AICohere(c *gin.Context) {
 		return
 	}
 
-	response, err := services.CallCohere(req.Text)
+	completion, ok := completionRequest(c, req)
+	if !ok {
+		return
+	}
+
+	response, cached, err := services.CallProviderWithCache(c.Request.Context(), c.Param("provider"), completion)
 	if err != nil {
-		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
 		return
 	}
 
-	c.String(http.StatusOK, response)
+	setCacheHeader(c, cached)
+
+	c.String(http.StatusOK, response.Text)
 }
 
-// AIMistral endpoint específico para Mistral
-func AIMistral(c *gin.Context) {
+// AIStream endpoint com fallback que devolve a resposta em Server-Sent Events.
+// Eventos: "token" ({"text": ...}) a cada trecho, "done" ({"provider", "model", "finish_reason"}) no fim
+// e "error" ({"error": ...}) se o provedor falhar depois do primeiro token.
+func AIStream(c *gin.Context) {
 	var req models.AIRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -56,52 +73,130 @@ func AIMistral(c *gin.Context) {
 		return
 	}
 
-	response, err := services.CallMistral(req.Text)
+	completion, ok := completionRequest(c, req)
+	if !ok {
+		return
+	}
+
+	started := false
+	onToken := func(token string) error {
+		if !started {
+			started = true
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no")
+		}
+
+		c.SSEvent("token", gin.H{"text": token})
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	}
+
+	response, err := services.StreamAIWithFallback(c.Request.Context(), completion, req.Providers, onToken)
 	if err != nil {
-		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		// Antes do primeiro token ainda dá para responder com o status correto
+		if !started {
+			utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
+			return
+		}
+
+		c.SSEvent("error", gin.H{"error": services.PublicAIError(err)})
+		c.Writer.Flush()
 		return
 	}
 
-	c.String(http.StatusOK, response)
+	c.SSEvent("done", gin.H{"provider": response.Provider, "model": response.Model, "finish_reason": response.FinishReason})
+	c.Writer.Flush()
 }
 
-// AIGroq endpoint específico para Groq
-func AIGroq(c *gin.Context) {
-	var req models.AIRequest
+// AIStructured endpoint que devolve JSON validado contra um schema ou tipo de exercício
+func AIStructured(c *gin.Context) {
+	var req models.StructuredAIRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": "Text input is required"})
 		return
 	}
 
-	response, err := services.CallGroq(req.Text)
+	schema := req.Schema
+	completion, ok := completionRequest(c, models.AIRequest{Text: req.Text, Persona: req.Persona, Model: req.Model, GenerationParams: req.GenerationParams})
+	if !ok {
+		return
+	}
+
+	if req.ExerciseType != "" {
+		exerciseSchema, instrucoes, err := services.ExerciseSchema(req.ExerciseType)
+		if err != nil {
+			utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err), "exercise_types": services.ExerciseTypes()})
+			return
+		}
+		if schema == nil {
+			schema = exerciseSchema
+		}
+		completion.Prompt = instrucoes + "\n\n" + completion.Prompt
+	}
+
+	if schema == nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": "schema or exercise_type is required", "exercise_types": services.ExerciseTypes()})
+		return
+	}
+
+	response, err := services.CallAIStructured(c.Request.Context(), completion, schema, req.Providers)
 	if err != nil {
-		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
 		return
 	}
 
-	c.String(http.StatusOK, response)
+	utils.SonicJSON(c, http.StatusOK, response)
 }
 
-// AIOpenRouter endpoint específico para OpenRouter
-func AIOpenRouter(c *gin.Context) {
-	var req models.AIRequest
+// AIGrammar corrige a frase do aluno no idioma que ele estuda, com as edições por posição
+// e categoria, e soma os pontos à skill Writing
+func AIGrammar(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
 
+	var req models.GrammarCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": "Text input is required"})
 		return
 	}
 
-	response, err := services.CallOpenRouter(req.Text)
+	response, err := services.CorrigirGramatica(c.Request.Context(), usuarioID, req)
+	if err != nil {
+		utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, response)
+}
+
+// AIUsage retorna o saldo (battery/tokens) e o consumo de IA do usuário nos últimos dias (?dias=30)
+func AIUsage(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	dias, err := strconv.Atoi(c.DefaultQuery("dias", "30"))
+	if err != nil || dias <= 0 {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": "dias inválido"})
+		return
+	}
+
+	resumo, err := services.ResumoUsoIA(usuarioID, dias)
 	if err != nil {
 		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.String(http.StatusOK, response)
+	utils.SonicJSON(c, http.StatusOK, resumo)
 }
 
-// AIBenchmark testa todas as IAs e retorna tempos de resposta
+// AIBenchmark testa todas as IAs em paralelo e retorna tempos, tokens e erros de cada uma
 func AIBenchmark(c *gin.Context) {
 	var req models.AIRequest
 
@@ -110,34 +205,143 @@ func AIBenchmark(c *gin.Context) {
 		return
 	}
 
-	results := make(models.BenchmarkResponse)
+	completion, ok := completionRequest(c, req)
+	if !ok {
+		return
+	}
 
-	// Função auxiliar para benchmark
-	benchmark := func(name string, fn func(string) (string, error)) {
-		start := time.Now()
-		response, err := fn(req.Text)
-		duration := time.Since(start).Seconds()
+	// O contexto da requisição é cancelado se o cliente desconectar, cancelando as chamadas pendentes
+	results := services.RunBenchmark(c.Request.Context(), completion)
 
-		if err != nil {
-			results[name] = models.AIResponse{
-				Response: "",
-				Error:    err.Error(),
-				Time:     duration,
-			}
-		} else {
-			results[name] = models.AIResponse{
-				Response: response,
-				Time:     duration,
-			}
+	utils.SonicJSON(c, http.StatusOK, results)
+}
+
+// AIBenchmarkLeaderboard compara os provedores pelos benchmarks dos últimos dias (?dias=7)
+func AIBenchmarkLeaderboard(c *gin.Context) {
+	dias, err := strconv.Atoi(c.DefaultQuery("dias", "7"))
+	if err != nil || dias <= 0 {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": "dias inválido"})
+		return
+	}
+
+	leaderboard, err := services.BenchmarkLeaderboard(dias)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, leaderboard)
+}
+
+// AIKeyPools mostra o estado dos pools de chaves de API de cada fornecedor (admin)
+func AIKeyPools(c *gin.Context) {
+	utils.SonicJSON(c, http.StatusOK, services.KeyPoolStatus())
+}
+
+// AIModels lista o catálogo de modelos (campo "model": fast, cheap, smart...)
+func AIModels(c *gin.Context) {
+	utils.SonicJSON(c, http.StatusOK, services.ModelCatalog())
+}
+
+// AIPersonas lista as personas disponíveis para o campo `persona`
+func AIPersonas(c *gin.Context) {
+	utils.SonicJSON(c, http.StatusOK, services.ListPersonas())
+}
+
+// completionRequest monta o pedido ao provedor, conferindo se o plano do usuário libera o
+// modelo escolhido e renderizando a persona com os dados do usuário.
+// Em caso de erro já responde e retorna false.
+func completionRequest(c *gin.Context, req models.AIRequest) (models.CompletionRequest, bool) {
+	completion := models.CompletionRequest{Prompt: req.Text, Model: req.Model, GenerationParams: req.GenerationParams}
+	if req.Persona == "" && req.Model == "" {
+		return completion, true
+	}
+
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return completion, false
+	}
+
+	if req.Model != "" {
+		if err := services.AutorizarModeloIA(usuarioID, req.Model); err != nil {
+			utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
+			return completion, false
 		}
 	}
 
-	// Executa benchmark de cada modelo
-	benchmark("Gemini", services.CallGemini)
-	benchmark("Mistral", services.CallMistral)
-	benchmark("Cohere", services.CallCohere)
-	benchmark("Groq", services.CallGroq)
-	benchmark("OpenRouter", services.CallOpenRouter)
+	if req.Persona == "" {
+		return completion, true
+	}
 
-	utils.SonicJSON(c, http.StatusOK, results)
-}
\ No newline at end of file
+	system, err := services.BuildSystemPrompt(usuarioID, req.Persona)
+	if err != nil {
+		utils.SonicJSON(c, aiErrorStatus(err), gin.H{"error": services.PublicAIError(err)})
+		return completion, false
+	}
+
+	completion.System = system
+	return completion, true
+}
+
+// setCacheHeader informa no header X-AI-Cache se a resposta veio do cache (HIT) ou do provedor (MISS)
+func setCacheHeader(c *gin.Context, cached bool) {
+	if cached {
+		c.Header("X-AI-Cache", "HIT")
+	} else {
+		c.Header("X-AI-Cache", "MISS")
+	}
+}
+
+// aiErrorStatus escolhe o status HTTP de acordo com o erro do serviço de IA
+func aiErrorStatus(err error) int {
+	var fallbackErr *services.FallbackError
+	if errors.As(err, &fallbackErr) {
+		return fallbackErrorStatus(fallbackErr)
+	}
+
+	switch {
+	case errors.Is(err, services.ErrProviderNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrPersonaNotFound), errors.Is(err, services.ErrExerciseTypeNotFound),
+		errors.Is(err, services.ErrModelNotFound), errors.Is(err, services.ErrInvalidGenerationParams):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrModelRequiresPremium):
+		return http.StatusForbidden
+	case errors.As(err, new(*services.ContentBlockedError)):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, services.ErrInvalidStructuredOutput):
+		return http.StatusBadGateway
+	case errors.Is(err, services.ErrProviderDisabled), errors.Is(err, services.ErrNoProviderAvailable),
+		errors.Is(err, services.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// fallbackErrorStatus escolhe o status quando todos os provedores da cadeia falharam.
+// Um erro do pedido (ex.: parâmetros fora do limite de um provedor) só vale se todos os
+// provedores falharam do mesmo jeito; senão vale a primeira falha de disponibilidade (5xx).
+func fallbackErrorStatus(err *services.FallbackError) int {
+	if len(err.Attempts) == 0 {
+		return http.StatusInternalServerError
+	}
+
+	first := aiErrorStatus(err.Attempts[0].Err)
+	unanimous := true
+	availability := 0
+	for _, attempt := range err.Attempts {
+		status := aiErrorStatus(attempt.Err)
+		if status != first {
+			unanimous = false
+		}
+		if availability == 0 && status >= http.StatusInternalServerError {
+			availability = status
+		}
+	}
+
+	if unanimous || availability == 0 {
+		return first
+	}
+	return availability
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/services"
+	"net/http"
+	"testing"
+)
+
+func TestAIErrorStatusFallback(t *testing.T) {
+	invalidParams := fmt.Errorf("%w: cohere: temperature deve estar entre 0 e 1", services.ErrInvalidGenerationParams)
+	unavailable := &services.ProviderStatusError{Provider: "gemini", StatusCode: http.StatusServiceUnavailable}
+
+	tests := []struct {
+		name     string
+		attempts []services.ProviderAttempt
+		want     int
+	}{
+		{
+			name: "todos recusaram os parâmetros",
+			attempts: []services.ProviderAttempt{
+				{Provider: "gemini", Err: invalidParams},
+				{Provider: "cohere", Err: invalidParams},
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "um recusou os parâmetros e outro estava fora do ar",
+			attempts: []services.ProviderAttempt{
+				{Provider: "cohere", Err: invalidParams},
+				{Provider: "mistral", Err: services.ErrCircuitOpen},
+				{Provider: "gemini", Err: unavailable},
+			},
+			want: http.StatusServiceUnavailable,
+		},
+		{
+			name: "falhas sem status de disponibilidade",
+			attempts: []services.ProviderAttempt{
+				{Provider: "cohere", Err: invalidParams},
+				{Provider: "gemini", Err: errors.New("connection reset")},
+			},
+			want: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &services.FallbackError{Attempts: tt.attempts}
+			if got := aiErrorStatus(err); got != tt.want {
+				t.Errorf("aiErrorStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"errors"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/services"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	authConfigOnce sync.Once
+	authConfig     config.AuthConfig
+)
+
+func getAuthConfig() config.AuthConfig {
+	authConfigOnce.Do(func() {
+		authConfig = config.GetAuthConfig()
+	})
+	return authConfig
+}
+
+// RefreshToken troca o refresh token por um access token novo e um refresh token rotacionado.
+// Na sessão por cookie o refresh token vem do cookie e o X-CSRF-Token deve trazer o csrf dele.
+func RefreshToken(c *gin.Context) {
+	var req services.RefreshTokenRequest
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "JSON inválido"})
+			return
+		}
+	}
+
+	cfg := getAuthConfig()
+	fromCookie := false
+	if req.RefreshToken == "" && cfg.CookieSessions {
+		if cookie, err := c.Cookie(config.RefreshTokenCookie); err == nil && cookie != "" {
+			req.RefreshToken = cookie
+			fromCookie = true
+		}
+	}
+	if req.RefreshToken == "" {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "refresh_token é obrigatório"})
+		return
+	}
+
+	if fromCookie {
+		claims, err := utils.ValidateToken(req.RefreshToken)
+		if err == nil && !utils.CSRFMatch(c.GetHeader(config.CSRFHeader), claims.CSRF) {
+			utils.SonicJSON(c, http.StatusForbidden, gin.H{"erro": "Token CSRF ausente ou inválido"})
+			return
+		}
+	}
+
+	response, err := services.RefreshToken(req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrRefreshTokenInvalido) || errors.Is(err, services.ErrRefreshTokenReutilizado) {
+			statusCode = http.StatusUnauthorized
+			if fromCookie {
+				limparCookiesSessao(c, cfg)
+			}
+		}
+		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+		return
+	}
+
+	if fromCookie {
+		csrf, err := definirCookiesSessao(c, cfg, response.AccessToken, response.RefreshToken)
+		if err != nil {
+			utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": "Erro ao renovar sessão"})
+			return
+		}
+		response = &services.RefreshTokenResponse{CSRFToken: csrf}
+	}
+
+	utils.SonicJSON(c, http.StatusOK, response)
+}
+
+// Logout revoga o access token atual (e a família do refresh_token, se enviado)
+func Logout(c *gin.Context) {
+	claims, ok := claimsDoContexto(c)
+	if !ok {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return
+	}
+
+	var req services.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "JSON inválido"})
+			return
+		}
+	}
+
+	cfg := getAuthConfig()
+	if req.RefreshToken == "" && cfg.CookieSessions {
+		req.RefreshToken, _ = c.Cookie(config.RefreshTokenCookie)
+	}
+
+	if err := services.Logout(claims, req); err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrRefreshTokenInvalido) || errors.Is(err, services.ErrTokenSemJTI) {
+			statusCode = http.StatusBadRequest
+		}
+		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+		return
+	}
+
+	if cfg.CookieSessions {
+		limparCookiesSessao(c, cfg)
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{"mensagem": "Logout realizado com sucesso"})
+}
+
+// LogoutTodos revoga todos os tokens do usuário emitidos até "antes" (padrão: agora)
+func LogoutTodos(c *gin.Context) {
+	claims, ok := claimsDoContexto(c)
+	if !ok {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return
+	}
+
+	var req services.LogoutTodosRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "JSON inválido: antes deve estar no formato RFC 3339"})
+			return
+		}
+	}
+
+	response, err := services.LogoutTodos(claims.Sub, req)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, services.ErrRevogacaoFutura) {
+			statusCode = http.StatusBadRequest
+		}
+		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+		return
+	}
+
+	if cfg := getAuthConfig(); cfg.CookieSessions {
+		limparCookiesSessao(c, cfg)
+	}
+
+	utils.SonicJSON(c, http.StatusOK, response)
+}
+
+// definirCookiesSessao grava os tokens em cookies HttpOnly e o csrf de cada um em um cookie
+// legível pelo JavaScript (double submit). Retorna o csrf do access token.
+func definirCookiesSessao(c *gin.Context, cfg config.AuthConfig, accessToken, refreshToken string) (string, error) {
+	access, err := utils.ValidateToken(accessToken)
+	if err != nil {
+		return "", err
+	}
+	refresh, err := utils.ValidateToken(refreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	accessMaxAge := int(utils.AccessTokenTTL.Seconds())
+	refreshMaxAge := int(utils.RefreshTokenTTL.Seconds())
+
+	c.SetSameSite(cfg.CookieSameSite)
+	c.SetCookie(config.AccessTokenCookie, accessToken, accessMaxAge, "/", cfg.CookieDomain, cfg.CookieSecure, true)
+	c.SetCookie(config.RefreshTokenCookie, refreshToken, refreshMaxAge, "/", cfg.CookieDomain, cfg.CookieSecure, true)
+	c.SetCookie(config.CSRFAccessCookie, access.CSRF, accessMaxAge, "/", cfg.CookieDomain, cfg.CookieSecure, false)
+	c.SetCookie(config.CSRFRefreshCookie, refresh.CSRF, refreshMaxAge, "/", cfg.CookieDomain, cfg.CookieSecure, false)
+
+	return access.CSRF, nil
+}
+
+// limparCookiesSessao expira os cookies da sessão
+func limparCookiesSessao(c *gin.Context, cfg config.AuthConfig) {
+	c.SetSameSite(cfg.CookieSameSite)
+	for _, name := range []string{config.AccessTokenCookie, config.RefreshTokenCookie, config.CSRFAccessCookie, config.CSRFRefreshCookie} {
+		c.SetCookie(name, "", -1, "/", cfg.CookieDomain, cfg.CookieSecure, name == config.AccessTokenCookie || name == config.RefreshTokenCookie)
+	}
+}
+
+// claimsDoContexto retorna os claims gravados pelo AuthMiddleware
+func claimsDoContexto(c *gin.Context) (*utils.Claims, bool) {
+	value, ok := c.Get("claims")
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*utils.Claims)
+	return claims, ok
+}
+
+// JWKS publica as chaves públicas que validam os tokens do Lingobot
+func JWKS(c *gin.Context) {
+	jwks, err := utils.JWKS()
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": "Erro ao carregar chaves do JWT"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	utils.SonicJSON(c, http.StatusOK, jwks)
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"lingobotAPI-GO/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// usuarioLogado retorna o ID do usuário autenticado (definido pelo AuthMiddleware).
+// Se não houver usuário, já responde 401 e retorna false.
+func usuarioLogado(c *gin.Context) (int, bool) {
+	usuarioID, ok := c.Get("user_id")
+	if !ok {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return 0, false
+	}
+
+	id, ok := usuarioID.(int)
+	if !ok || id <= 0 {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return 0, false
+	}
+
+	return id, true
+}
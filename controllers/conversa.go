@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"errors"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/services"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CriarConversa abre uma nova conversa com o tutor
+func CriarConversa(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	var req models.CriarConversaRequest
+	// Corpo opcional: sem título a conversa é criada sem nome
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Dados inválidos"})
+			return
+		}
+	}
+
+	conversa, err := services.CriarConversa(usuarioID, req)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusCreated, conversa)
+}
+
+// GetConversas lista as conversas do usuário logado
+func GetConversas(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	conversas, err := services.ListarConversas(usuarioID)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, conversas)
+}
+
+// GetConversa retorna a conversa com o histórico de mensagens
+func GetConversa(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	conversaID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	conversa, err := services.GetConversa(usuarioID, conversaID)
+	if err != nil {
+		utils.SonicJSON(c, conversaErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, conversa)
+}
+
+// EnviarMensagem envia uma mensagem na conversa e retorna a resposta do tutor
+func EnviarMensagem(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	conversaID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	var req models.EnviarMensagemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Texto é obrigatório"})
+		return
+	}
+
+	resposta, err := services.EnviarMensagem(c.Request.Context(), usuarioID, conversaID, req)
+	if err != nil {
+		utils.SonicJSON(c, conversaErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, resposta)
+}
+
+// ApagarConversa remove uma conversa do usuário
+func ApagarConversa(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	conversaID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	if err := services.ApagarConversa(usuarioID, conversaID); err != nil {
+		utils.SonicJSON(c, conversaErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{"mensagem": "Conversa apagada com sucesso!"})
+}
+
+// conversaErrorStatus escolhe o status HTTP para erros de conversa
+func conversaErrorStatus(err error) int {
+	if errors.Is(err, services.ErrConversaNaoEncontrada) {
+		return http.StatusNotFound
+	}
+	return aiErrorStatus(err)
+}
@@ -35,7 +35,7 @@ func TTS(c *gin.Context) {
 	fmt.Printf("🔊 Gerando TTS para: %.60s... (voz %d) | Premium: %t\n", text, voiceIndex, premium)
 
 	// Gera o áudio
-	audioData, err := services.GenerateTTS(text, voiceIndex, premium)
+	audioData, err := services.GenerateTTS(c.Request.Context(), text, voiceIndex, premium)
 	if err != nil {
 		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": "Erro ao gerar áudio"})
 		return
@@ -67,9 +67,9 @@ func TranscribeAudio(c *gin.Context) {
 	fmt.Printf("📝 Transcrevendo áudio: %s\n", file.Filename)
 
 	// Transcreve o áudio
-	text, err := services.TranscribeAudio(tempPath)
+	text, err := services.TranscribeAudio(c.Request.Context(), tempPath)
 	if err != nil {
-		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"error": services.PublicAIError(err)})
 		return
 	}
 
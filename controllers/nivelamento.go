@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/services"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IniciarNivelamento abre um teste de nivelamento CEFR para o usuário logado
+func IniciarNivelamento(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	nivelamento, err := services.IniciarNivelamento(usuarioID)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusCreated, nivelamento)
+}
+
+// GetNivelamento retorna o teste com as questões já geradas e respondidas
+func GetNivelamento(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	nivelamentoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	detalhe, err := services.GetNivelamento(usuarioID, nivelamentoID)
+	if err != nil {
+		utils.SonicJSON(c, nivelamentoErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, detalhe)
+}
+
+// ProximaQuestaoNivelamento retorna a próxima questão do teste (gerada no nível atual)
+func ProximaQuestaoNivelamento(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	nivelamentoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	questao, err := services.ProximaQuestaoNivelamento(c.Request.Context(), usuarioID, nivelamentoID)
+	if err != nil {
+		utils.SonicJSON(c, nivelamentoErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, questao)
+}
+
+// ResponderNivelamento corrige a resposta e devolve o estado do teste (com o nível CEFR ao concluir)
+func ResponderNivelamento(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	nivelamentoID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	var req models.ResponderNivelamentoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "questao_id e resposta são obrigatórios"})
+		return
+	}
+
+	resultado, err := services.ResponderNivelamento(c.Request.Context(), usuarioID, nivelamentoID, req)
+	if err != nil {
+		utils.SonicJSON(c, nivelamentoErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, resultado)
+}
+
+// nivelamentoErrorStatus escolhe o status HTTP para erros do teste de nivelamento
+func nivelamentoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNivelamentoNaoEncontrado), errors.Is(err, services.ErrQuestaoNaoEncontrada):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNivelamentoConcluido), errors.Is(err, services.ErrQuestaoJaRespondida):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrRespostaInvalida):
+		return http.StatusBadRequest
+	default:
+		return aiErrorStatus(err)
+	}
+}
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"lingobotAPI-GO/repositories"
 	"lingobotAPI-GO/services"
 	"lingobotAPI-GO/utils"
@@ -51,12 +52,30 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	cfg := getAuthConfig()
+	if req.Cookie && !cfg.CookieSessions {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Sessão por cookie desativada"})
+		return
+	}
+
 	response, err := services.Login(req)
 	if err != nil {
 		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": err.Error()})
 		return
 	}
 
+	// Cliente web: tokens só nos cookies HttpOnly, fora do alcance do JavaScript
+	if req.Cookie {
+		csrf, err := definirCookiesSessao(c, cfg, response.AccessToken, response.RefreshToken)
+		if err != nil {
+			utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": "Erro ao criar sessão"})
+			return
+		}
+		response.AccessToken = ""
+		response.RefreshToken = ""
+		response.CSRFToken = csrf
+	}
+
 	utils.SonicJSON(c, http.StatusOK, response)
 }
 
@@ -68,13 +87,25 @@ func UpdateUserData(c *gin.Context) {
 		return
 	}
 
-	response, err := services.UpdateUserData(req)
+	claims, ok := claimsDoContexto(c)
+	if !ok {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return
+	}
+
+	response, err := services.UpdateUserData(claims, req)
 	if err != nil {
 		statusCode := http.StatusBadRequest
 
 		if err.Error() == "usuário não encontrado" {
 			statusCode = http.StatusNotFound
 		}
+		if errors.Is(err, services.ErrTokenNaoFresh) {
+			statusCode = http.StatusUnauthorized
+		}
+		if errors.Is(err, services.ErrUsuarioDiferente) {
+			statusCode = http.StatusForbidden
+		}
 
 		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
 		return
@@ -83,6 +114,73 @@ func UpdateUserData(c *gin.Context) {
 	utils.SonicJSON(c, http.StatusOK, response)
 }
 
+// AlterarSenha troca a senha do usuário autenticado (exige token fresh e a senha atual)
+func AlterarSenha(c *gin.Context) {
+	var req services.AlterarSenhaRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Dados inválidos"})
+		return
+	}
+
+	claims, ok := claimsDoContexto(c)
+	if !ok {
+		utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+		return
+	}
+
+	if err := services.AlterarSenha(claims, req); err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if errors.Is(err, services.ErrSenhaVazia) {
+			statusCode = http.StatusBadRequest
+		}
+		if errors.Is(err, services.ErrTokenNaoFresh) || errors.Is(err, services.ErrSenhaIncorreta) {
+			statusCode = http.StatusUnauthorized
+		}
+		if err.Error() == "usuário não encontrado" {
+			statusCode = http.StatusNotFound
+		}
+
+		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{"mensagem": "Senha alterada com sucesso"})
+}
+
+// DefinirPlanoUsuario altera o plano de um usuário (admin), liberando ou não os modelos premium
+func DefinirPlanoUsuario(c *gin.Context) {
+	usuarioID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	var req services.DefinirPlanoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Dados inválidos"})
+		return
+	}
+
+	plano, err := services.DefinirPlano(usuarioID, req.Plano)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+
+		if errors.Is(err, services.ErrPlanoInvalido) {
+			statusCode = http.StatusBadRequest
+		}
+		if errors.Is(err, services.ErrUsuarioNaoEncontrado) {
+			statusCode = http.StatusNotFound
+		}
+
+		utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{"mensagem": "Plano atualizado", "usuario_id": usuarioID, "plano": plano})
+}
+
 // GetUsuarioProfile retorna dados básicos do perfil (nome, email, etc)
 func GetUsuarioProfile(c *gin.Context) {
 	idParam := c.Param("id")
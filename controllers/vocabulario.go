@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"errors"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/services"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdicionarVocabulario adiciona uma palavra ao baralho do usuário logado
+func AdicionarVocabulario(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	var req models.AdicionarVocabularioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Palavra é obrigatória"})
+		return
+	}
+
+	cartao, err := services.AdicionarVocabulario(usuarioID, req)
+	if err != nil {
+		utils.SonicJSON(c, vocabularioErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusCreated, cartao)
+}
+
+// ExtrairVocabulario adiciona ao baralho as palavras que a IA encontrar em um texto ou mensagem
+func ExtrairVocabulario(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	var req models.ExtrairVocabularioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "Dados inválidos"})
+		return
+	}
+
+	adicionadas, err := services.ExtrairVocabulario(c.Request.Context(), usuarioID, req)
+	if err != nil {
+		utils.SonicJSON(c, vocabularioErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, adicionadas)
+}
+
+// GetVocabulario lista o baralho do usuário no idioma que ele estuda
+func GetVocabulario(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	cartoes, err := services.ListarVocabulario(usuarioID)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, cartoes)
+}
+
+// GetVocabularioPendente lista os cartões com revisão vencida (?limite=20)
+func GetVocabularioPendente(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	limite, err := strconv.Atoi(c.DefaultQuery("limite", "0"))
+	if err != nil || limite < 0 {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "limite inválido"})
+		return
+	}
+
+	cartoes, err := services.VocabularioPendente(usuarioID, limite)
+	if err != nil {
+		utils.SonicJSON(c, http.StatusInternalServerError, gin.H{"erro": err.Error()})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, cartoes)
+}
+
+// RevisarVocabulario registra a nota de lembrança (0-5) e devolve o cartão reagendado
+func RevisarVocabulario(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	vocabularioID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	var req models.RevisarVocabularioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "nota deve ser de 0 a 5"})
+		return
+	}
+
+	cartao, err := services.RevisarVocabulario(usuarioID, vocabularioID, *req.Nota)
+	if err != nil {
+		utils.SonicJSON(c, vocabularioErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, cartao)
+}
+
+// ApagarVocabulario remove uma palavra do baralho
+func ApagarVocabulario(c *gin.Context) {
+	usuarioID, ok := usuarioLogado(c)
+	if !ok {
+		return
+	}
+
+	vocabularioID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.SonicJSON(c, http.StatusBadRequest, gin.H{"erro": "ID inválido"})
+		return
+	}
+
+	if err := services.ApagarVocabulario(usuarioID, vocabularioID); err != nil {
+		utils.SonicJSON(c, vocabularioErrorStatus(err), gin.H{"erro": services.PublicAIError(err)})
+		return
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{"mensagem": "Palavra removida do vocabulário!"})
+}
+
+// vocabularioErrorStatus escolhe o status HTTP para erros do vocabulário
+func vocabularioErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrVocabularioNaoEncontrado), errors.Is(err, services.ErrMensagemNaoEncontrada):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrVocabularioDuplicado):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrTextoVocabulario), errors.Is(err, services.ErrPalavraVazia):
+		return http.StatusBadRequest
+	default:
+		return aiErrorStatus(err)
+	}
+}
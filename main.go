@@ -6,6 +6,7 @@ import (
 	"lingobotAPI-GO/config"
 	"lingobotAPI-GO/routes"
 	"lingobotAPI-GO/utils"
+	"log"
 )
 
 func main() {
@@ -20,6 +21,11 @@ func main() {
 	// Inicializar o banco de dados (vem do config/database.go)
 	config.ConnectDatabase()
 
+	// Valida as chaves do JWT (depois do .env, carregado pelo ConnectDatabase)
+	if err := utils.LoadJWTKeys(); err != nil {
+		log.Fatalf("❌ Erro nas chaves do JWT: %v", err)
+	}
+
 	// Registrar as rotas (vem de routes/routes.go)
 	routes.RegisterRoutes(router)
 
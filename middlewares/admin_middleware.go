@@ -0,0 +1,35 @@
+package middlewares
+
+import (
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware libera a rota apenas para os usuários listados em ADMIN_USER_IDS
+// (ids separados por vírgula). Deve ser usado depois do AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuarioID, ok := c.Get("user_id")
+		id, isInt := usuarioID.(int)
+		if !ok || !isInt {
+			utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		for _, item := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+			if adminID, err := strconv.Atoi(strings.TrimSpace(item)); err == nil && adminID == id {
+				c.Next()
+				return
+			}
+		}
+
+		utils.SonicJSON(c, http.StatusForbidden, gin.H{"erro": "Acesso restrito a administradores"})
+		c.Abort()
+	}
+}
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"errors"
+	"lingobotAPI-GO/services"
+	"lingobotAPI-GO/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AIQuotaMiddleware cobra a requisição de IA da battery/tokens do usuário (conforme o plano)
+// e marca o contexto para que o consumo de tokens seja gravado. Se o handler responder
+// com erro, o débito é estornado. Deve ser usado depois do AuthMiddleware.
+func AIQuotaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usuarioID, ok := c.Get("user_id")
+		id, isInt := usuarioID.(int)
+		if !ok || !isInt {
+			utils.SonicJSON(c, http.StatusUnauthorized, gin.H{"erro": "Usuário não autenticado"})
+			c.Abort()
+			return
+		}
+
+		reserva, err := services.ReservarCotaIA(id)
+		if err != nil {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, services.ErrCotaIAEsgotada) {
+				statusCode = http.StatusPaymentRequired
+			}
+			utils.SonicJSON(c, statusCode, gin.H{"erro": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(services.ContextWithUsuarioIA(c.Request.Context(), id))
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			services.EstornarCotaIA(reserva)
+		}
+	}
+}
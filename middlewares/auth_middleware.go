@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/services"
 	"lingobotAPI-GO/utils"
 	"net/http"
 	"strings"
@@ -8,12 +10,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware valida o JWT antes de permitir acesso à rota
+// AuthMiddleware valida o JWT antes de permitir acesso à rota. Só aceita access tokens, do header
+// Authorization ou (com AUTH_COOKIES) do cookie de sessão. O X-CSRF-Token, quando enviado, deve bater
+// com o claim csrf; é obrigatório nos métodos que alteram dados com cookie ou AUTH_CSRF_REQUIRED.
 func AuthMiddleware() gin.HandlerFunc {
+	cfg := config.GetAuthConfig()
+
 	return func(c *gin.Context) {
 		// Pega o header Authorization
 		authHeader := c.GetHeader("Authorization")
 
+		// Sem header, o cliente web manda o token no cookie HttpOnly
+		fromCookie := false
+		if authHeader == "" && cfg.CookieSessions {
+			if cookie, err := c.Cookie(config.AccessTokenCookie); err == nil && cookie != "" {
+				authHeader = "Bearer " + cookie
+				fromCookie = true
+			}
+		}
+
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"erro": "Token não fornecido"})
 			c.Abort()
@@ -38,6 +53,32 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Refresh tokens só servem para o /token/refresh
+		if claims.Type != "access" {
+			c.JSON(http.StatusUnauthorized, gin.H{"erro": "Tipo de token inválido: use o access token"})
+			c.Abort()
+			return
+		}
+
+		if !csrfValido(c, claims, cfg.RequireCSRF || fromCookie) {
+			c.JSON(http.StatusForbidden, gin.H{"erro": "Token CSRF ausente ou inválido"})
+			c.Abort()
+			return
+		}
+
+		// Recusa tokens revogados por logout (JTI) ou por logout em todos os dispositivos
+		revogado, err := services.TokenRevogado(claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro ao validar token"})
+			c.Abort()
+			return
+		}
+		if revogado {
+			c.JSON(http.StatusUnauthorized, gin.H{"erro": "Token revogado"})
+			c.Abort()
+			return
+		}
+
 		// Armazena os claims no contexto para uso posterior
 		c.Set("user_id", claims.Sub)
 		c.Set("claims", claims)
@@ -46,3 +87,33 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// FreshTokenMiddleware exige um token emitido pelo login com senha (services.RequireFreshToken)
+// nas rotas sensíveis. Deve ser usado depois do AuthMiddleware.
+func FreshTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get("claims")
+		claims, _ := value.(*utils.Claims)
+		if err := services.RequireFreshToken(claims); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"erro": "Operação exige login recente: faça login novamente"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// csrfValido confere o X-CSRF-Token com o claim csrf. Sem o header, só passa se o CSRF não é
+// exigido ou se o método não altera dados (GET, HEAD, OPTIONS).
+func csrfValido(c *gin.Context, claims *utils.Claims, required bool) bool {
+	header := c.GetHeader(config.CSRFHeader)
+	if header == "" {
+		return !required || metodoSeguro(c.Request.Method)
+	}
+	return utils.CSRFMatch(header, claims.CSRF)
+}
+
+func metodoSeguro(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET_KEY", "test-jwt-secret") // a chave JWT é carregada uma vez
+	os.Exit(m.Run())
+}
+
+// tokenDeTeste gera um access token e devolve também o claim csrf dele
+func tokenDeTeste(t *testing.T) (string, string) {
+	t.Helper()
+
+	token, err := utils.GenerateAccessToken(1, nil)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+	claims, err := utils.ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	return token, claims.CSRF
+}
+
+func TestAuthMiddlewareCSRF(t *testing.T) {
+	t.Setenv("AUTH_COOKIES", "true")
+
+	router := gin.New()
+	router.POST("/", AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, _ := tokenDeTeste(t)
+
+	tests := []struct {
+		name   string
+		cookie bool
+		csrf   string
+	}{
+		{name: "cookie com csrf de outro token", cookie: true, csrf: "outro-csrf"},
+		{name: "cookie sem o header", cookie: true},
+		{name: "bearer com csrf de outro token", csrf: "outro-csrf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.cookie {
+				req.AddCookie(&http.Cookie{Name: config.AccessTokenCookie, Value: token})
+			} else {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			if tt.csrf != "" {
+				req.Header.Set(config.CSRFHeader, tt.csrf)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestCSRFValido(t *testing.T) {
+	claims := &utils.Claims{CSRF: "csrf-do-token"}
+
+	tests := []struct {
+		name     string
+		method   string
+		header   string
+		required bool
+		want     bool
+	}{
+		{name: "header igual ao claim", method: http.MethodPost, header: "csrf-do-token", required: true, want: true},
+		{name: "header diferente do claim", method: http.MethodPost, header: "outro", required: true, want: false},
+		{name: "header diferente sem exigência", method: http.MethodPost, header: "outro", want: false},
+		{name: "sem header quando exigido", method: http.MethodPost, required: true, want: false},
+		{name: "sem header em método seguro", method: http.MethodGet, required: true, want: true},
+		{name: "sem header sem exigência", method: http.MethodPost, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(tt.method, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(config.CSRFHeader, tt.header)
+			}
+
+			if got := csrfValido(c, claims, tt.required); got != tt.want {
+				t.Errorf("csrfValido() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFreshTokenMiddleware(t *testing.T) {
+	tests := []struct {
+		name  string
+		fresh bool
+		want  int
+	}{
+		{name: "token do login", fresh: true, want: http.StatusOK},
+		{name: "token renovado", fresh: false, want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.PUT("/",
+				func(c *gin.Context) { c.Set("claims", &utils.Claims{Sub: 1, Fresh: tt.fresh}) },
+				FreshTokenMiddleware(),
+				func(c *gin.Context) { c.Status(http.StatusOK) },
+			)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/", nil))
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+-- Conversas do usuário com o tutor (IA) e o histórico de mensagens
+CREATE TABLE IF NOT EXISTS conversa (
+    id         SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    titulo     VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversa_usuario ON conversa (usuario_id, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS conversa_mensagem (
+    id          SERIAL PRIMARY KEY,
+    conversa_id INTEGER NOT NULL REFERENCES conversa (id) ON DELETE CASCADE,
+    role        VARCHAR(16) NOT NULL CHECK (role IN ('user', 'assistant')),
+    content     TEXT NOT NULL,
+    provider    VARCHAR(32),
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversa_mensagem_conversa ON conversa_mensagem (conversa_id, id);
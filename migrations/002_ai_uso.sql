@@ -0,0 +1,13 @@
+-- Consumo de tokens das chamadas de IA por usuário e provedor
+CREATE TABLE IF NOT EXISTS ai_uso (
+    id                SERIAL PRIMARY KEY,
+    usuario_id        INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    provider          VARCHAR(32) NOT NULL,
+    model             VARCHAR(128),
+    prompt_tokens     INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    estimado          BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_uso_usuario ON ai_uso (usuario_id, created_at DESC);
@@ -0,0 +1,10 @@
+-- Cache de respostas de IA para prompts idênticos (backend "postgres" do AI_CACHE)
+CREATE TABLE IF NOT EXISTS ai_cache (
+    cache_key  CHAR(64) PRIMARY KEY,
+    response   JSONB NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_cache_expires ON ai_cache (expires_at);
+CREATE INDEX IF NOT EXISTS idx_ai_cache_created ON ai_cache (created_at);
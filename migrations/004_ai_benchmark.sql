@@ -0,0 +1,17 @@
+-- Histórico das execuções do /ai/benchmark (uma linha por provedor em cada execução)
+CREATE TABLE IF NOT EXISTS ai_benchmark (
+    id             SERIAL PRIMARY KEY,
+    run_id         UUID NOT NULL,
+    usuario_id     INTEGER REFERENCES usuario (id) ON DELETE SET NULL,
+    prompt         TEXT NOT NULL,
+    provider       VARCHAR(32) NOT NULL,
+    model          VARCHAR(128),
+    latency_ms     INTEGER NOT NULL,
+    success        BOOLEAN NOT NULL,
+    error_category VARCHAR(32),
+    output_length  INTEGER NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_benchmark_created ON ai_benchmark (created_at, provider);
+CREATE INDEX IF NOT EXISTS idx_ai_benchmark_run ON ai_benchmark (run_id);
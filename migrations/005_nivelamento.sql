@@ -0,0 +1,35 @@
+-- Nível CEFR do usuário, definido pelo teste de nivelamento
+ALTER TABLE usuario_progresso ADD COLUMN IF NOT EXISTS cefr VARCHAR(2)
+    CHECK (cefr IN ('A1', 'A2', 'B1', 'B2', 'C1', 'C2'));
+
+-- Teste de nivelamento adaptativo: o nível da próxima questão sobe ou desce a cada resposta
+CREATE TABLE IF NOT EXISTS nivelamento (
+    id             SERIAL PRIMARY KEY,
+    usuario_id     INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    status         VARCHAR(16) NOT NULL DEFAULT 'em_andamento' CHECK (status IN ('em_andamento', 'concluido', 'abandonado')),
+    nivel_atual    VARCHAR(2) NOT NULL,
+    total_questoes INTEGER NOT NULL,
+    cefr           VARCHAR(2),
+    created_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    concluido_em   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_nivelamento_usuario ON nivelamento (usuario_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS nivelamento_questao (
+    id               SERIAL PRIMARY KEY,
+    nivelamento_id   INTEGER NOT NULL REFERENCES nivelamento (id) ON DELETE CASCADE,
+    ordem            INTEGER NOT NULL,
+    cefr             VARCHAR(2) NOT NULL,
+    tipo             VARCHAR(20) NOT NULL CHECK (tipo IN ('multiple-choice', 'writing')),
+    enunciado        TEXT NOT NULL,
+    opcoes           JSONB,
+    resposta_correta INTEGER,
+    explicacao       TEXT,
+    resposta         TEXT,
+    correta          BOOLEAN,
+    feedback         TEXT,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    respondida_em    TIMESTAMP,
+    UNIQUE (nivelamento_id, ordem)
+);
@@ -0,0 +1,19 @@
+-- Baralho de vocabulário do usuário com agendamento de revisões no estilo SM-2
+CREATE TABLE IF NOT EXISTS vocabulario (
+    id              SERIAL PRIMARY KEY,
+    usuario_id      INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    idioma          TEXT NOT NULL, -- usuario_progresso.learning (texto livre) quando a palavra entrou
+    palavra         VARCHAR(255) NOT NULL,
+    traducao        TEXT,
+    exemplo         TEXT,
+    origem          VARCHAR(16) NOT NULL DEFAULT 'manual' CHECK (origem IN ('manual', 'ia')),
+    facilidade      DOUBLE PRECISION NOT NULL DEFAULT 2.5, -- ease factor do SM-2 (mínimo 1.3)
+    intervalo       INTEGER NOT NULL DEFAULT 0,            -- dias até a próxima revisão
+    repeticoes      INTEGER NOT NULL DEFAULT 0,            -- acertos seguidos
+    proxima_revisao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ultima_revisao  TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vocabulario_palavra ON vocabulario (usuario_id, idioma, LOWER(palavra));
+CREATE INDEX IF NOT EXISTS idx_vocabulario_revisao ON vocabulario (usuario_id, idioma, proxima_revisao);
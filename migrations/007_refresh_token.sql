@@ -0,0 +1,14 @@
+-- Refresh tokens emitidos, agrupados por família (uma família por login).
+-- Cada refresh gera um token novo na mesma família; reapresentar um token já usado revoga a família.
+CREATE TABLE IF NOT EXISTS refresh_token (
+    jti         UUID PRIMARY KEY,
+    familia     UUID NOT NULL,
+    usuario_id  INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    expira_em   TIMESTAMP NOT NULL,
+    usado_em    TIMESTAMP,
+    revogado_em TIMESTAMP,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_familia ON refresh_token (familia);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_usuario ON refresh_token (usuario_id);
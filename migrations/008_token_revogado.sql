@@ -0,0 +1,16 @@
+-- Tokens revogados individualmente (logout), guardados até expirarem
+CREATE TABLE IF NOT EXISTS token_revogado (
+    jti        UUID PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuario (id) ON DELETE CASCADE,
+    expira_em  TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_revogado_expira ON token_revogado (expira_em);
+
+-- "Sair de todos os dispositivos": tokens emitidos até revogado_antes deixam de valer
+CREATE TABLE IF NOT EXISTS usuario_revogacao (
+    usuario_id     INTEGER PRIMARY KEY REFERENCES usuario (id) ON DELETE CASCADE,
+    revogado_antes TIMESTAMP NOT NULL,
+    updated_at     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Recarga da battery com o tempo: uma carga por intervalo, contado a partir de battery_recarga_em
+ALTER TABLE usuario_economia
+    ADD COLUMN IF NOT EXISTS battery_recarga_em TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,207 @@
+// Package mockai é um servidor falso que fala o protocolo dos fornecedores de IA
+// (Gemini, Mistral, Cohere, Groq, OpenRouter, ElevenLabs e AssemblyAI).
+//
+// Cada fornecedor fica em um prefixo próprio (/gemini, /mistral, ...) seguido do
+// caminho oficial da API, que é o formato gerado por AI_MOCK_URL em config.AIConfig.
+// As respostas seguem um roteiro (Script) com textos, erros, 429 e atrasos, para
+// testar fallback, retry e circuit breaker sem chaves reais.
+package mockai
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Providers são os fornecedores atendidos pelo servidor falso
+var Providers = []string{"gemini", "mistral", "cohere", "groq", "openrouter", "elevenlabs", "assemblyai"}
+
+// Step é uma resposta roteirizada
+type Step struct {
+	Status       int    `json:"status"`        // 0 = 200
+	Text         string `json:"text"`          // texto gerado (ou transcrição, no AssemblyAI)
+	Error        string `json:"error"`         // mensagem de erro no formato do fornecedor (status >= 400)
+	FinishReason string `json:"finish_reason"` // ex.: "SAFETY" no Gemini, "length" nas APIs OpenAI
+	RetryAfter   string `json:"retry_after"`   // header Retry-After (segundos ou data HTTP)
+	Delay        string `json:"delay"`         // espera antes de responder ("3s")
+	ChunkDelay   string `json:"chunk_delay"`   // espera entre os chunks do streaming
+	Times        int    `json:"times"`         // quantas requisições o passo atende (0 = 1)
+}
+
+// Script é o roteiro de cada fornecedor. Os passos são consumidos em ordem e o último
+// se repete; sem passos, o fornecedor responde sempre com sucesso.
+type Script map[string][]Step
+
+// Server é o servidor falso com o roteiro atual e a contagem de chamadas
+type Server struct {
+	mu     sync.Mutex
+	script Script
+	pos    map[string]int // próximo passo de cada fornecedor
+	used   map[string]int // quantas vezes o passo atual já foi usado
+	calls  map[string]int
+}
+
+// New cria o servidor com o roteiro informado (nil = sempre sucesso)
+func New(script Script) *Server {
+	s := &Server{}
+	s.SetScript(script)
+	return s
+}
+
+// LoadScript lê um roteiro de um arquivo JSON (veja mockai.example.json)
+func LoadScript(path string) (Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var script Script
+	if err := utils.Unmarshal(data, &script); err != nil {
+		return nil, fmt.Errorf("roteiro inválido: %v", err)
+	}
+
+	return script, nil
+}
+
+// SetScript troca o roteiro e zera as posições e a contagem de chamadas
+func (s *Server) SetScript(script Script) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if script == nil {
+		script = Script{}
+	}
+	s.script = script
+	s.pos = map[string]int{}
+	s.used = map[string]int{}
+	s.calls = map[string]int{}
+}
+
+// Calls retorna quantas requisições cada fornecedor recebeu
+func (s *Server) Calls() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := make(map[string]int, len(s.calls))
+	for name, n := range s.calls {
+		calls[name] = n
+	}
+	return calls
+}
+
+// next consome o próximo passo do roteiro do fornecedor
+func (s *Server) next(provider string) Step {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[provider]++
+
+	steps := s.script[provider]
+	if len(steps) == 0 {
+		return Step{}
+	}
+
+	i := s.pos[provider]
+	if i >= len(steps) {
+		return steps[len(steps)-1]
+	}
+
+	step := steps[i]
+	s.used[provider]++
+	if s.used[provider] >= max(step.Times, 1) {
+		s.pos[provider] = i + 1
+		s.used[provider] = 0
+	}
+	return step
+}
+
+// Handler monta as rotas de todos os fornecedores e as rotas de controle (/_mock)
+func (s *Server) Handler() http.Handler {
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery())
+
+	// Controle do roteiro durante os testes
+	router.GET("/_mock/calls", func(c *gin.Context) {
+		utils.SonicJSON(c, http.StatusOK, s.Calls())
+	})
+	router.PUT("/_mock/script", func(c *gin.Context) {
+		var script Script
+		if err := c.ShouldBindJSON(&script); err != nil {
+			utils.SonicJSON(c, http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		s.SetScript(script)
+		c.Status(http.StatusNoContent)
+	})
+	router.POST("/_mock/reset", func(c *gin.Context) {
+		s.mu.Lock()
+		script := s.script
+		s.mu.Unlock()
+		s.SetScript(script)
+		c.Status(http.StatusNoContent)
+	})
+
+	router.POST("/gemini/v1beta/models/:action", s.gemini)
+	router.POST("/mistral/v1/chat/completions", s.openAIChat("mistral"))
+	router.POST("/groq/openai/v1/chat/completions", s.openAIChat("groq"))
+	router.POST("/openrouter/api/v1/chat/completions", s.openAIChat("openrouter"))
+	router.POST("/cohere/v1/chat", s.cohere)
+	router.POST("/elevenlabs/v1/text-to-speech/:voice", s.elevenLabs)
+	router.POST("/assemblyai/v2/upload", s.assemblyAIUpload)
+	router.POST("/assemblyai/v2/transcript", s.assemblyAITranscript)
+	router.GET("/assemblyai/v2/transcript/:id", s.assemblyAIPoll)
+
+	return router
+}
+
+// play consome o passo do fornecedor, aplica o atraso e responde os erros roteirizados.
+// Retorna false quando a resposta já foi enviada (erro) ou o cliente desistiu.
+func (s *Server) play(c *gin.Context, provider string, errorBody func(status int, message string) interface{}) (Step, bool) {
+	step := s.next(provider)
+
+	if !wait(c.Request.Context(), step.Delay) {
+		return step, false
+	}
+
+	if step.Status == 0 || step.Status == http.StatusOK {
+		return step, true
+	}
+
+	if step.RetryAfter != "" {
+		c.Header("Retry-After", step.RetryAfter)
+	}
+	message := step.Error
+	if message == "" {
+		message = fmt.Sprintf("mock %s error %d", provider, step.Status)
+	}
+	utils.SonicJSON(c, step.Status, errorBody(step.Status, message))
+	return step, false
+}
+
+// wait espera a duração do passo ("" = sem espera); retorna false se o cliente cancelar
+func wait(ctx context.Context, delay string) bool {
+	if delay == "" {
+		return true
+	}
+
+	d, err := time.ParseDuration(delay)
+	if err != nil || d <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,361 @@
+package mockai
+
+import (
+	"fmt"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockAudio é um MP3 mínimo (só o cabeçalho ID3) devolvido como áudio do ElevenLabs
+var mockAudio = []byte{'I', 'D', '3', 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+var mockIDs atomic.Int64
+
+// mockID gera ids únicos para respostas e transcrições
+func mockID(prefix string) string {
+	return fmt.Sprintf("%s-%d", prefix, mockIDs.Add(1))
+}
+
+// chatRequest são os campos do corpo da requisição que o servidor falso usa
+type chatRequest struct {
+	Model         string `json:"model"`
+	Stream        bool   `json:"stream"`
+	StreamOptions struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options"`
+}
+
+// readRequest lê o corpo da requisição e estima os tokens do prompt
+func readRequest(c *gin.Context) (chatRequest, int) {
+	var req chatRequest
+	body, _ := c.GetRawData()
+	utils.Unmarshal(body, &req)
+	return req, estimateTokens(string(body))
+}
+
+// responseText é o texto do passo ou uma resposta padrão com o nome do fornecedor
+func responseText(step Step, provider string) string {
+	if step.Text != "" {
+		return step.Text
+	}
+	return fmt.Sprintf("Mock response from %s.", provider)
+}
+
+// estimateTokens estima tokens como o serviço faz (~4 caracteres por token)
+func estimateTokens(text string) int {
+	return (len([]rune(text)) + 3) / 4
+}
+
+// streamSSE envia cada chunk como um evento `data:`, esperando ChunkDelay entre eles
+func streamSSE(c *gin.Context, step Step, chunks []interface{}, done bool) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+
+	for i, chunk := range chunks {
+		if i > 0 && !wait(c.Request.Context(), step.ChunkDelay) {
+			return
+		}
+		data, err := utils.Marshal(chunk)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+		c.Writer.Flush()
+	}
+
+	if done {
+		fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+		c.Writer.Flush()
+	}
+}
+
+// splitWords divide o texto em trechos (palavra + espaço) para simular o streaming
+func splitWords(text string) []string {
+	return strings.SplitAfter(text, " ")
+}
+
+// gemini atende generateContent e streamGenerateContent (ex.: gemini-2.0-flash:generateContent)
+func (s *Server) gemini(c *gin.Context) {
+	model, method, _ := strings.Cut(c.Param("action"), ":")
+	if method != "generateContent" && method != "streamGenerateContent" {
+		utils.SonicJSON(c, http.StatusNotFound, geminiError(http.StatusNotFound, "method not found: "+method))
+		return
+	}
+
+	_, promptTokens := readRequest(c)
+	step, ok := s.play(c, "gemini", geminiError)
+	if !ok {
+		return
+	}
+
+	text := responseText(step, "gemini")
+	finishReason := step.FinishReason
+	if finishReason == "" {
+		finishReason = "STOP"
+	}
+
+	usage := gin.H{
+		"promptTokenCount":     promptTokens,
+		"candidatesTokenCount": estimateTokens(text),
+		"totalTokenCount":      promptTokens + estimateTokens(text),
+	}
+
+	// Bloqueio de segurança: o candidato volta sem conteúdo
+	if finishReason == "SAFETY" {
+		response := gin.H{
+			"candidates": []gin.H{{
+				"finishReason": "SAFETY",
+				"index":        0,
+				"safetyRatings": []gin.H{{
+					"category":    "HARM_CATEGORY_HARASSMENT",
+					"probability": "HIGH",
+					"blocked":     true,
+				}},
+			}},
+			"usageMetadata": gin.H{"promptTokenCount": promptTokens},
+			"modelVersion":  model,
+		}
+		if method == "streamGenerateContent" {
+			streamSSE(c, step, []interface{}{response}, false)
+			return
+		}
+		utils.SonicJSON(c, http.StatusOK, response)
+		return
+	}
+
+	candidate := func(text string, finishReason string) gin.H {
+		candidate := gin.H{
+			"content": gin.H{"role": "model", "parts": []gin.H{{"text": text}}},
+			"index":   0,
+		}
+		if finishReason != "" {
+			candidate["finishReason"] = finishReason
+		}
+		return candidate
+	}
+
+	if method == "generateContent" {
+		utils.SonicJSON(c, http.StatusOK, gin.H{
+			"candidates":    []gin.H{candidate(text, finishReason)},
+			"usageMetadata": usage,
+			"modelVersion":  model,
+		})
+		return
+	}
+
+	words := splitWords(text)
+	chunks := make([]interface{}, 0, len(words))
+	for i, word := range words {
+		chunk := gin.H{"candidates": []gin.H{candidate(word, "")}, "modelVersion": model}
+		if i == len(words)-1 {
+			chunk["candidates"] = []gin.H{candidate(word, finishReason)}
+			chunk["usageMetadata"] = usage
+		}
+		chunks = append(chunks, chunk)
+	}
+	streamSSE(c, step, chunks, false)
+}
+
+// geminiError segue o formato de erro das APIs do Google
+func geminiError(status int, message string) interface{} {
+	statusName := "INTERNAL"
+	switch status {
+	case http.StatusBadRequest:
+		statusName = "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		statusName = "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		statusName = "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		statusName = "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		statusName = "RESOURCE_EXHAUSTED"
+	case http.StatusServiceUnavailable:
+		statusName = "UNAVAILABLE"
+	}
+	return gin.H{"error": gin.H{"code": status, "message": message, "status": statusName}}
+}
+
+// openAIChat atende o /chat/completions das APIs compatíveis com OpenAI (Mistral, Groq, OpenRouter)
+func (s *Server) openAIChat(provider string) gin.HandlerFunc {
+	errorBody := openAIError
+	if provider == "mistral" {
+		errorBody = mistralError
+	}
+
+	return func(c *gin.Context) {
+		req, promptTokens := readRequest(c)
+		step, ok := s.play(c, provider, errorBody)
+		if !ok {
+			return
+		}
+
+		text := responseText(step, provider)
+		finishReason := step.FinishReason
+		if finishReason == "" {
+			finishReason = "stop"
+		}
+		model := req.Model
+		if model == "" {
+			model = provider + "-mock"
+		}
+
+		id := mockID("chatcmpl")
+		usage := gin.H{
+			"prompt_tokens":     promptTokens,
+			"completion_tokens": estimateTokens(text),
+			"total_tokens":      promptTokens + estimateTokens(text),
+		}
+
+		if !req.Stream {
+			utils.SonicJSON(c, http.StatusOK, gin.H{
+				"id":      id,
+				"object":  "chat.completion",
+				"created": time.Now().Unix(),
+				"model":   model,
+				"choices": []gin.H{{
+					"index":         0,
+					"message":       gin.H{"role": "assistant", "content": text},
+					"finish_reason": finishReason,
+				}},
+				"usage": usage,
+			})
+			return
+		}
+
+		chunk := func(delta gin.H, finishReason interface{}) gin.H {
+			return gin.H{
+				"id":      id,
+				"object":  "chat.completion.chunk",
+				"created": time.Now().Unix(),
+				"model":   model,
+				"choices": []gin.H{{"index": 0, "delta": delta, "finish_reason": finishReason}},
+			}
+		}
+
+		var chunks []interface{}
+		for _, word := range splitWords(text) {
+			chunks = append(chunks, chunk(gin.H{"content": word}, nil))
+		}
+		chunks = append(chunks, chunk(gin.H{}, finishReason))
+		if req.StreamOptions.IncludeUsage {
+			chunks = append(chunks, gin.H{
+				"id":      id,
+				"object":  "chat.completion.chunk",
+				"created": time.Now().Unix(),
+				"model":   model,
+				"choices": []gin.H{},
+				"usage":   usage,
+			})
+		}
+		streamSSE(c, step, chunks, true)
+	}
+}
+
+// openAIError segue o formato de erro da OpenAI (Groq e OpenRouter)
+func openAIError(status int, message string) interface{} {
+	return gin.H{"error": gin.H{"message": message, "type": "mock_error", "code": status}}
+}
+
+// mistralError segue o formato de erro da Mistral
+func mistralError(status int, message string) interface{} {
+	return gin.H{"object": "error", "message": message, "type": "mock_error", "code": fmt.Sprint(status)}
+}
+
+// cohere atende o /v1/chat da Cohere
+func (s *Server) cohere(c *gin.Context) {
+	_, promptTokens := readRequest(c)
+	step, ok := s.play(c, "cohere", func(status int, message string) interface{} {
+		return gin.H{"message": message}
+	})
+	if !ok {
+		return
+	}
+
+	text := responseText(step, "cohere")
+	finishReason := step.FinishReason
+	if finishReason == "" {
+		finishReason = "COMPLETE"
+	}
+
+	utils.SonicJSON(c, http.StatusOK, gin.H{
+		"response_id":   mockID("cohere"),
+		"generation_id": mockID("generation"),
+		"text":          text,
+		"finish_reason": finishReason,
+		"chat_history":  []gin.H{},
+		"meta": gin.H{
+			"billed_units": gin.H{
+				"input_tokens":  promptTokens,
+				"output_tokens": estimateTokens(text),
+			},
+		},
+	})
+}
+
+// elevenLabs atende o text-to-speech e devolve um áudio vazio
+func (s *Server) elevenLabs(c *gin.Context) {
+	if _, ok := s.play(c, "elevenlabs", elevenLabsError); !ok {
+		return
+	}
+	c.Data(http.StatusOK, "audio/mpeg", mockAudio)
+}
+
+// elevenLabsError segue o formato de erro do ElevenLabs
+func elevenLabsError(status int, message string) interface{} {
+	return gin.H{"detail": gin.H{"status": "mock_error", "message": message}}
+}
+
+// assemblyAIUpload aceita qualquer arquivo e devolve uma URL do próprio servidor falso
+func (s *Server) assemblyAIUpload(c *gin.Context) {
+	if _, ok := s.play(c, "assemblyai", assemblyAIError); !ok {
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	utils.SonicJSON(c, http.StatusOK, gin.H{
+		"upload_url": fmt.Sprintf("%s://%s/assemblyai/v2/files/%s", scheme, c.Request.Host, mockID("upload")),
+	})
+}
+
+// assemblyAITranscript cria a transcrição, que já nasce na fila
+func (s *Server) assemblyAITranscript(c *gin.Context) {
+	if _, ok := s.play(c, "assemblyai", assemblyAIError); !ok {
+		return
+	}
+	utils.SonicJSON(c, http.StatusOK, gin.H{"id": mockID("transcript"), "status": "queued"})
+}
+
+// assemblyAIPoll conclui a transcrição no primeiro polling. Um passo com status 200 e
+// `error` preenchido simula a falha da transcrição (status "error" no corpo).
+func (s *Server) assemblyAIPoll(c *gin.Context) {
+	step, ok := s.play(c, "assemblyai", assemblyAIError)
+	if !ok {
+		return
+	}
+
+	if step.Error != "" {
+		utils.SonicJSON(c, http.StatusOK, gin.H{"id": c.Param("id"), "status": "error", "error": step.Error})
+		return
+	}
+
+	text := step.Text
+	if text == "" {
+		text = "Mock transcription."
+	}
+	utils.SonicJSON(c, http.StatusOK, gin.H{"id": c.Param("id"), "status": "completed", "text": text})
+}
+
+// assemblyAIError segue o formato de erro do AssemblyAI
+func assemblyAIError(status int, message string) interface{} {
+	return gin.H{"error": message}
+}
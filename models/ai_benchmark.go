@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// AIBenchmarkResultado - Resultado de um provedor em uma execução do benchmark
+type AIBenchmarkResultado struct {
+	ID            int       `json:"id" db:"id"`
+	RunID         string    `json:"run_id" db:"run_id"`
+	UsuarioID     *int      `json:"usuario_id" db:"usuario_id"`
+	Prompt        string    `json:"prompt" db:"prompt"`
+	Provider      string    `json:"provider" db:"provider"`
+	Model         *string   `json:"model" db:"model"`
+	LatencyMs     int       `json:"latency_ms" db:"latency_ms"`
+	Success       bool      `json:"success" db:"success"`
+	ErrorCategory *string   `json:"error_category" db:"error_category"`
+	OutputLength  int       `json:"output_length" db:"output_length"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// AIProviderRanking - Estatísticas agregadas de um provedor no leaderboard
+type AIProviderRanking struct {
+	Provider        string  `json:"provider"`
+	Execucoes       int     `json:"execucoes"`
+	SuccessRate     float64 `json:"success_rate"`
+	P50LatencyMs    float64 `json:"p50_latency_ms"`
+	P95LatencyMs    float64 `json:"p95_latency_ms"`
+	AvgOutputLength float64 `json:"avg_output_length"`
+}
+
+// AILeaderboard - Resposta do /ai/benchmark/leaderboard
+type AILeaderboard struct {
+	Desde     time.Time           `json:"desde"`
+	Ate       time.Time           `json:"ate"`
+	Providers []AIProviderRanking `json:"providers"`
+}
@@ -1,19 +1,150 @@
 package models
 
+import "time"
+
 // AIRequest representa a requisição para os endpoints de IA
 type AIRequest struct {
-	Text    string `json:"text" binding:"required"`
-	Mistral bool   `json:"mistral,omitempty"`
-	Cohere  bool   `json:"cohere,omitempty"`
-	Groq    bool   `json:"groq,omitempty"`
+	Text      string   `json:"text" binding:"required"`
+	Providers []string `json:"providers,omitempty"` // Cadeia de provedores a tentar, em ordem (vazio = configuração)
+	Persona   string   `json:"persona,omitempty"`   // Template de system prompt (ex.: grammar-corrector)
+	Model     string   `json:"model,omitempty"`     // Apelido do modelo: fast, cheap ou smart (vazio = padrão)
+	GenerationParams
+}
+
+// GenerationParams são os parâmetros opcionais de geração, validados contra os limites de
+// cada provedor (vazio = padrão: temperature 0.7 e max_tokens do provedor)
+type GenerationParams struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
 }
 
 // AIResponse representa a resposta dos serviços de IA
 type AIResponse struct {
-	Response string  `json:"response,omitempty"`
-	Error    string  `json:"error,omitempty"`
-	Time     float64 `json:"time_seconds,omitempty"`
+	Response      string   `json:"response,omitempty"`
+	Model         string   `json:"model,omitempty"`
+	Usage         *AIUsage `json:"usage,omitempty"`
+	FinishReason  string   `json:"finish_reason,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	ErrorCategory string   `json:"error_category,omitempty"` // timeout, rate_limited, auth, unavailable...
+	Time          float64  `json:"time_seconds,omitempty"`
 }
 
 // BenchmarkResponse representa o resultado do benchmark
 type BenchmarkResponse map[string]AIResponse
+
+// ProviderHealth representa o estado de saúde de um provedor de IA (exposto no /health)
+type ProviderHealth struct {
+	Name                string     `json:"name"`
+	Enabled             bool       `json:"enabled"`
+	State               string     `json:"state"` // closed, open ou half-open
+	ErrorRate           float64    `json:"error_rate"`
+	RecentCalls         int        `json:"recent_calls"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastErrorCategory   string     `json:"last_error_category,omitempty"` // timeout, network, unavailable ou rate_limited
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"`
+}
+
+// APIKeyStatus é o estado de uma chave de API de um fornecedor (a chave vem mascarada)
+type APIKeyStatus struct {
+	Name         string     `json:"name"` // variável de ambiente
+	Key          string     `json:"key"`
+	Requests     int        `json:"requests"`
+	Successes    int        `json:"successes"`
+	Failures     int        `json:"failures"`
+	QuotaErrors  int        `json:"quota_errors"`
+	Benched      bool       `json:"benched"`
+	BenchedUntil *time.Time `json:"benched_until,omitempty"`
+	LastUsed     *time.Time `json:"last_used,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
+
+// APIKeyPoolStatus é o estado do pool de chaves de um fornecedor
+type APIKeyPoolStatus struct {
+	Provider  string         `json:"provider"`
+	Available int            `json:"available"`
+	Keys      []APIKeyStatus `json:"keys"`
+}
+
+// Papéis das mensagens de chat
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// AIMessage representa uma mensagem do histórico de uma conversa
+type AIMessage struct {
+	Role    string `json:"role"` // user ou assistant
+	Content string `json:"content"`
+}
+
+// CompletionRequest é o pedido enviado a um provedor de IA.
+// System traz as instruções do tutor (persona), History as mensagens anteriores
+// da conversa e Prompt a mensagem atual do usuário. ResponseSchema, quando presente, pede saída JSON estruturada aos provedores que suportam.
+type CompletionRequest struct {
+	System         string                 `json:"system,omitempty"`
+	Prompt         string                 `json:"prompt"`
+	History        []AIMessage            `json:"history,omitempty"`
+	ResponseSchema map[string]interface{} `json:"response_schema,omitempty"`
+	Model          string                 `json:"model,omitempty"` // apelido do catálogo (vazio = padrão)
+	GenerationParams
+}
+
+// AIModelInfo descreve um apelido do catálogo de modelos e o modelo de cada provedor
+type AIModelInfo struct {
+	Alias     string              `json:"alias"`
+	Default   bool                `json:"default"`
+	Premium   bool                `json:"premium"`
+	Providers map[string][]string `json:"providers"`
+}
+
+// PersonaInfo descreve uma persona disponível para o tutor
+type PersonaInfo struct {
+	Name      string `json:"name"`
+	Descricao string `json:"descricao"`
+}
+
+// Valores normalizados de finish_reason (o motivo de o provedor ter parado de gerar)
+const (
+	FinishReasonStop          = "stop"           // fim natural ou sequência de stop
+	FinishReasonLength        = "length"         // atingiu max_tokens
+	FinishReasonContentFilter = "content_filter" // bloqueado pelo filtro de segurança
+	FinishReasonOther         = "other"
+)
+
+// AIUsage representa o consumo de tokens de uma chamada ao provedor
+type AIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// CompletionResponse é a resposta devolvida por um provedor de IA
+type CompletionResponse struct {
+	Text         string  `json:"text"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model,omitempty"`
+	Usage        AIUsage `json:"usage"`
+	FinishReason string  `json:"finish_reason,omitempty"` // stop, length, content_filter ou other
+}
+
+// StructuredAIRequest representa a requisição de saída estruturada (JSON).
+// Informe Schema (JSON Schema) ou ExerciseType (multiple-choice, fill-in-the-blank, sentence-correction).
+type StructuredAIRequest struct {
+	Text         string                 `json:"text" binding:"required"`
+	Schema       map[string]interface{} `json:"schema,omitempty"`
+	ExerciseType string                 `json:"exercise_type,omitempty"`
+	Providers    []string               `json:"providers,omitempty"`
+	Persona      string                 `json:"persona,omitempty"`
+	Model        string                 `json:"model,omitempty"`
+	GenerationParams
+}
+
+// StructuredAIResponse representa a resposta validada contra o schema
+type StructuredAIResponse struct {
+	Data     interface{} `json:"data"`
+	Provider string      `json:"provider"`
+	Model    string      `json:"model,omitempty"`
+	Attempts int         `json:"attempts"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AIUso - Registro de consumo de tokens de uma chamada de IA
+type AIUso struct {
+	ID               int       `json:"id" db:"id"`
+	UsuarioID        int       `json:"usuario_id" db:"usuario_id"`
+	Provider         string    `json:"provider" db:"provider"`
+	Model            *string   `json:"model" db:"model"`
+	PromptTokens     int       `json:"prompt_tokens" db:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens" db:"completion_tokens"`
+	Estimado         bool      `json:"estimado" db:"estimado"` // true quando o provedor não informou o uso
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// AIUsoProvider - Consumo agregado de um provedor
+type AIUsoProvider struct {
+	Provider         string `json:"provider"`
+	Requisicoes      int    `json:"requisicoes"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+}
+
+// AIUsoResumo - Resposta do /ai/usage: saldo atual e consumo do período
+type AIUsoResumo struct {
+	Plano          string          `json:"plano"`
+	Battery        int             `json:"battery"`
+	ProximaRecarga *time.Time      `json:"proxima_recarga,omitempty"` // próxima carga da battery (nil = cheia)
+	Tokens         int             `json:"tokens"`
+	Desde          time.Time       `json:"desde"`
+	Providers      []AIUsoProvider `json:"providers"`
+}
+
+// ReservaCotaIA - Débito feito antes da chamada de IA (estornado se a chamada falhar)
+type ReservaCotaIA struct {
+	UsuarioID int `json:"usuario_id"`
+	Battery   int `json:"battery"`
+	Tokens    int `json:"tokens"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Conversa - Conversa do usuário com o tutor (IA)
+type Conversa struct {
+	ID        int       `json:"id" db:"id"`
+	UsuarioID int       `json:"usuario_id" db:"usuario_id"`
+	Titulo    *string   `json:"titulo" db:"titulo"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ConversaMensagem - Mensagem de uma conversa
+type ConversaMensagem struct {
+	ID         int       `json:"id" db:"id"`
+	ConversaID int       `json:"conversa_id" db:"conversa_id"`
+	Role       string    `json:"role" db:"role"` // user ou assistant
+	Content    string    `json:"content" db:"content"`
+	Provider   *string   `json:"provider,omitempty" db:"provider"` // provedor que gerou a resposta
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// ConversaDetalhe - DTO com a conversa e suas mensagens
+type ConversaDetalhe struct {
+	Conversa  Conversa           `json:"conversa"`
+	Mensagens []ConversaMensagem `json:"mensagens"`
+}
+
+// CriarConversaRequest representa a requisição para abrir uma conversa
+type CriarConversaRequest struct {
+	Titulo *string `json:"titulo"`
+}
+
+// EnviarMensagemRequest representa a requisição para enviar uma mensagem na conversa
+type EnviarMensagemRequest struct {
+	Text      string   `json:"text" binding:"required"`
+	Providers []string `json:"providers,omitempty"`
+	Persona   string   `json:"persona,omitempty"`
+	Model     string   `json:"model,omitempty"`
+	GenerationParams
+}
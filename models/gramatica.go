@@ -0,0 +1,39 @@
+package models
+
+// Categorias de erro da correção gramatical
+const (
+	GrammarCategoryTense     = "tense"      // tempo verbal
+	GrammarCategoryAgreement = "agreement"  // concordância (sujeito-verbo, gênero, número)
+	GrammarCategorySpelling  = "spelling"   // ortografia
+	GrammarCategoryWordOrder = "word_order" // ordem das palavras
+	GrammarCategoryOther     = "other"      // vocabulário, preposições, pontuação...
+)
+
+// GrammarCheckRequest representa a frase do aluno a ser corrigida no idioma que ele estuda
+type GrammarCheckRequest struct {
+	Text      string   `json:"text" binding:"required"`
+	Providers []string `json:"providers,omitempty"`
+	Model     string   `json:"model,omitempty"`
+}
+
+// GrammarEdit é uma correção na frase original. Start e End são posições em caracteres
+// (code points) no texto enviado, com End exclusivo: texto[Start:End] == Original.
+type GrammarEdit struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+	Category    string `json:"category"` // tense, agreement, spelling, word_order ou other
+	Explanation string `json:"explanation"`
+}
+
+// GrammarCheckResponse traz a frase corrigida, as edições e os pontos de Writing ganhos
+type GrammarCheckResponse struct {
+	Original      string        `json:"original"`
+	Corrected     string        `json:"corrected"`
+	Edits         []GrammarEdit `json:"edits"`
+	WritingPoints int           `json:"writing_points"` // pontos somados à skill Writing
+	Writing       int           `json:"writing"`        // skill Writing atualizada
+	Provider      string        `json:"provider"`
+	Model         string        `json:"model,omitempty"`
+}
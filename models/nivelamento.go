@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Status do teste de nivelamento
+const (
+	NivelamentoEmAndamento = "em_andamento"
+	NivelamentoConcluido   = "concluido"
+	NivelamentoAbandonado  = "abandonado" // substituído por um teste novo antes de terminar
+)
+
+// Tipos de questão do teste de nivelamento
+const (
+	QuestaoMultiplaEscolha = "multiple-choice"
+	QuestaoEscrita         = "writing"
+)
+
+// Nivelamento - Teste adaptativo que define o nível CEFR (A1–C2) do usuário
+type Nivelamento struct {
+	ID            int        `json:"id" db:"id"`
+	UsuarioID     int        `json:"usuario_id" db:"usuario_id"`
+	Status        string     `json:"status" db:"status"`
+	NivelAtual    string     `json:"nivel_atual" db:"nivel_atual"` // nível CEFR da próxima questão
+	TotalQuestoes int        `json:"total_questoes" db:"total_questoes"`
+	Respondidas   int        `json:"respondidas" db:"-"`
+	Cefr          *string    `json:"cefr" db:"cefr"` // resultado, preenchido ao concluir
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	ConcluidoEm   *time.Time `json:"concluido_em" db:"concluido_em"`
+}
+
+// NivelamentoQuestao - Questão do teste, gerada pela IA no nível CEFR do momento
+type NivelamentoQuestao struct {
+	ID              int        `json:"id" db:"id"`
+	NivelamentoID   int        `json:"nivelamento_id" db:"nivelamento_id"`
+	Ordem           int        `json:"ordem" db:"ordem"`
+	Cefr            string     `json:"cefr" db:"cefr"`
+	Tipo            string     `json:"tipo" db:"tipo"` // multiple-choice ou writing
+	Enunciado       string     `json:"enunciado" db:"enunciado"`
+	Opcoes          []string   `json:"opcoes,omitempty" db:"opcoes"`
+	RespostaCorreta *int       `json:"-" db:"resposta_correta"` // índice em Opcoes; nunca exposto antes da resposta
+	Explicacao      *string    `json:"-" db:"explicacao"`       // vira o feedback da multiple-choice
+	Resposta        *string    `json:"resposta,omitempty" db:"resposta"`
+	Correta         *bool      `json:"correta,omitempty" db:"correta"`
+	Feedback        *string    `json:"feedback,omitempty" db:"feedback"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	RespondidaEm    *time.Time `json:"respondida_em,omitempty" db:"respondida_em"`
+}
+
+// NivelamentoDetalhe - DTO com o teste e as questões já geradas
+type NivelamentoDetalhe struct {
+	Nivelamento Nivelamento          `json:"nivelamento"`
+	Questoes    []NivelamentoQuestao `json:"questoes"`
+}
+
+// ResponderNivelamentoRequest representa a resposta a uma questão do teste.
+// Em multiple-choice, Resposta é o índice da opção (0-3) ou o texto dela.
+type ResponderNivelamentoRequest struct {
+	QuestaoID int    `json:"questao_id" binding:"required"`
+	Resposta  string `json:"resposta" binding:"required"`
+}
+
+// NivelamentoResultado - Correção da resposta e o estado do teste depois dela
+type NivelamentoResultado struct {
+	Correta         bool        `json:"correta"`
+	Feedback        string      `json:"feedback"`
+	RespostaCorreta *int        `json:"resposta_correta,omitempty"` // multiple-choice
+	Nivelamento     Nivelamento `json:"nivelamento"`
+	Difficulty      string      `json:"difficulty,omitempty"` // gravada no progresso ao concluir
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RefreshToken - Refresh token emitido; a família agrupa os tokens gerados a partir de um login
+type RefreshToken struct {
+	JTI        string     `json:"jti" db:"jti"`
+	Familia    string     `json:"familia" db:"familia"`
+	UsuarioID  int        `json:"usuario_id" db:"usuario_id"`
+	ExpiraEm   time.Time  `json:"expira_em" db:"expira_em"`
+	UsadoEm    *time.Time `json:"usado_em" db:"usado_em"`
+	RevogadoEm *time.Time `json:"revogado_em" db:"revogado_em"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -23,15 +23,21 @@ type UsuarioSeguranca struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
-// UsuarioEconomia - Moedas, tokens e recursos
+// BatteryMax é a carga máxima da battery (nova conta começa cheia; recargas e estornos não passam disso)
+const BatteryMax = 10
+
+// UsuarioEconomia - Moedas, tokens e recursos. Tokens, battery e plano só mudam no servidor
+// (cobrança de IA, recarga da battery com o tempo e assinatura, que libera os modelos premium);
+// o /update-user-data não altera esses campos.
 type UsuarioEconomia struct {
-	ID        int       `json:"id" db:"id"`
-	UsuarioID int       `json:"usuario_id" db:"usuario_id"`
-	Tokens    int       `json:"tokens" db:"tokens"`
-	Gemas     int       `json:"gemas" db:"gemas"`
-	Battery   int       `json:"battery" db:"battery"`
-	Plano     string    `json:"plano" db:"plano"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID               int       `json:"id" db:"id"`
+	UsuarioID        int       `json:"usuario_id" db:"usuario_id"`
+	Tokens           int       `json:"tokens" db:"tokens"`
+	Gemas            int       `json:"gemas" db:"gemas"`
+	Battery          int       `json:"battery" db:"battery"`
+	BatteryRecargaEm time.Time `json:"-" db:"battery_recarga_em"` // início da contagem da próxima carga (UTC)
+	Plano            string    `json:"plano" db:"plano"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // UsuarioProgresso - XP, level, skills
@@ -47,6 +53,7 @@ type UsuarioProgresso struct {
 	Ranking    int       `json:"ranking" db:"ranking"`
 	Difficulty string    `json:"difficulty" db:"difficulty"`
 	Learning   string    `json:"learning" db:"learning"`
+	Cefr       *string   `json:"cefr" db:"cefr"` // A1–C2, definido pelo teste de nivelamento
 	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
 }
 
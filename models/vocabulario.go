@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Origem da palavra no baralho
+const (
+	VocabularioManual = "manual"
+	VocabularioIA     = "ia" // extraída de um texto ou resposta da IA
+)
+
+// Vocabulario - Cartão do baralho de revisão espaçada (SM-2) do usuário
+type Vocabulario struct {
+	ID             int        `json:"id" db:"id"`
+	UsuarioID      int        `json:"usuario_id" db:"usuario_id"`
+	Idioma         string     `json:"idioma" db:"idioma"`
+	Palavra        string     `json:"palavra" db:"palavra"`
+	Traducao       *string    `json:"traducao" db:"traducao"`
+	Exemplo        *string    `json:"exemplo" db:"exemplo"`
+	Origem         string     `json:"origem" db:"origem"`
+	Facilidade     float64    `json:"facilidade" db:"facilidade"` // ease factor
+	Intervalo      int        `json:"intervalo" db:"intervalo"`   // em dias
+	Repeticoes     int        `json:"repeticoes" db:"repeticoes"`
+	ProximaRevisao time.Time  `json:"proxima_revisao" db:"proxima_revisao"`
+	UltimaRevisao  *time.Time `json:"ultima_revisao" db:"ultima_revisao"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AdicionarVocabularioRequest representa a palavra adicionada manualmente ao baralho
+type AdicionarVocabularioRequest struct {
+	Palavra  string  `json:"palavra" binding:"required"`
+	Traducao *string `json:"traducao"`
+	Exemplo  *string `json:"exemplo"`
+}
+
+// ExtrairVocabularioRequest pede à IA as palavras de estudo de um texto ou de uma mensagem
+// de conversa do usuário (informe Text ou MensagemID)
+type ExtrairVocabularioRequest struct {
+	Text       string   `json:"text"`
+	MensagemID int      `json:"mensagem_id"`
+	Providers  []string `json:"providers,omitempty"`
+}
+
+// RevisarVocabularioRequest representa a nota de lembrança da revisão (SM-2):
+// 0 = esqueceu totalmente ... 3 = lembrou com esforço ... 5 = lembrou na hora
+type RevisarVocabularioRequest struct {
+	Nota *int `json:"nota" binding:"required,min=0,max=5"`
+}
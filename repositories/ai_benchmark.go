@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InsertAIBenchmark grava os resultados de uma execução do benchmark (batch)
+func InsertAIBenchmark(resultados []models.AIBenchmarkResultado) error {
+	if len(resultados) == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO ai_benchmark (
+			run_id, usuario_id, prompt, provider, model,
+			latency_ms, success, error_category, output_length
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	batch := &pgx.Batch{}
+	for _, r := range resultados {
+		batch.Queue(query,
+			r.RunID, r.UsuarioID, r.Prompt, r.Provider, r.Model,
+			r.LatencyMs, r.Success, r.ErrorCategory, r.OutputLength,
+		)
+	}
+
+	if err := config.DB.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("erro ao inserir ai_benchmark: %v", err)
+	}
+
+	return nil
+}
+
+// GetAIBenchmarkRanking agrega latência (p50/p95) e taxa de sucesso por provedor no período.
+// As latências consideram apenas as execuções com sucesso.
+func GetAIBenchmarkRanking(desde, ate time.Time) ([]models.AIProviderRanking, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT
+			provider,
+			COUNT(*),
+			AVG(CASE WHEN success THEN 1.0 ELSE 0.0 END),
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY latency_ms) FILTER (WHERE success), 0),
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY latency_ms) FILTER (WHERE success), 0),
+			COALESCE(AVG(output_length) FILTER (WHERE success), 0)
+		FROM ai_benchmark
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY provider
+		ORDER BY 3 DESC, 4 ASC
+	`
+
+	rows, err := config.DB.Query(ctx, query, desde, ate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ranking := []models.AIProviderRanking{}
+	for rows.Next() {
+		var r models.AIProviderRanking
+		if err := rows.Scan(
+			&r.Provider, &r.Execucoes, &r.SuccessRate,
+			&r.P50LatencyMs, &r.P95LatencyMs, &r.AvgOutputLength,
+		); err != nil {
+			return nil, err
+		}
+		ranking = append(ranking, r)
+	}
+
+	return ranking, rows.Err()
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"time"
+)
+
+// GetAICache retorna a resposta em cache (JSON) se existir e não estiver expirada
+func GetAICache(ctx context.Context, key string) ([]byte, error) {
+	query := `
+		SELECT response
+		FROM ai_cache
+		WHERE cache_key = $1 AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	var response []byte
+	if err := config.DB.QueryRow(ctx, query, key).Scan(&response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// UpsertAICache grava (ou substitui) a resposta em cache
+func UpsertAICache(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	query := `
+		INSERT INTO ai_cache (cache_key, response, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + $3 * INTERVAL '1 second')
+		ON CONFLICT (cache_key) DO UPDATE SET
+			response = EXCLUDED.response, expires_at = EXCLUDED.expires_at, created_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := config.DB.Exec(ctx, query, key, response, int(ttl.Seconds())); err != nil {
+		return fmt.Errorf("erro ao gravar ai_cache: %v", err)
+	}
+
+	return nil
+}
+
+// PruneAICache remove entradas expiradas e as mais antigas além de maxEntries
+func PruneAICache(ctx context.Context, maxEntries int) error {
+	if _, err := config.DB.Exec(ctx, `DELETE FROM ai_cache WHERE expires_at <= CURRENT_TIMESTAMP`); err != nil {
+		return fmt.Errorf("erro ao limpar ai_cache: %v", err)
+	}
+
+	query := `
+		DELETE FROM ai_cache
+		WHERE cache_key IN (
+			SELECT cache_key FROM ai_cache
+			ORDER BY created_at DESC
+			OFFSET $1
+		)
+	`
+
+	if _, err := config.DB.Exec(ctx, query, maxEntries); err != nil {
+		return fmt.Errorf("erro ao limitar ai_cache: %v", err)
+	}
+
+	return nil
+}
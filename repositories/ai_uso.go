@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"time"
+)
+
+// GetUsuarioEconomia retorna tokens, gemas, battery (com o início da próxima recarga) e plano do usuário
+func GetUsuarioEconomia(usuarioID int) (*models.UsuarioEconomia, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, usuario_id, tokens, gemas, battery, battery_recarga_em, plano, updated_at
+		FROM usuario_economia
+		WHERE usuario_id = $1
+	`
+
+	var e models.UsuarioEconomia
+	err := config.DB.QueryRow(ctx, query, usuarioID).Scan(
+		&e.ID, &e.UsuarioID, &e.Tokens, &e.Gemas, &e.Battery, &e.BatteryRecargaEm, &e.Plano, &e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// DebitarEconomia debita battery e tokens apenas se houver saldo suficiente.
+// Retorna false quando o saldo não cobre o débito (nada é alterado).
+func DebitarEconomia(usuarioID, battery, tokens int) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE usuario_economia SET
+			battery = battery - $2, tokens = tokens - $3, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1 AND battery >= $2 AND tokens >= $3
+	`
+
+	tag, err := config.DB.Exec(ctx, query, usuarioID, battery, tokens)
+	if err != nil {
+		return false, fmt.Errorf("erro ao debitar usuario_economia: %v", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// CreditarEconomia devolve battery (limitada a models.BatteryMax) e tokens ao usuário
+func CreditarEconomia(usuarioID, battery, tokens int) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE usuario_economia SET
+			battery = LEAST(battery + $2, $4), tokens = tokens + $3, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1
+	`
+
+	if _, err := config.DB.Exec(ctx, query, usuarioID, battery, tokens, models.BatteryMax); err != nil {
+		return fmt.Errorf("erro ao creditar usuario_economia: %v", err)
+	}
+
+	return nil
+}
+
+// UpdatePlano altera o plano do usuário. Retorna false se o usuário não tiver economia.
+func UpdatePlano(usuarioID int, plano string) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE usuario_economia SET plano = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1
+	`
+
+	tag, err := config.DB.Exec(ctx, query, usuarioID, plano)
+	if err != nil {
+		return false, fmt.Errorf("erro ao atualizar plano: %v", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecarregarBateria soma as cargas recuperadas com o tempo (limitada a models.BatteryMax) e
+// move o início da contagem de `de` para `para`. Retorna false se battery_recarga_em não
+// for mais `de` (outra requisição recarregou antes; nada é alterado).
+func RecarregarBateria(usuarioID, cargas int, de, para time.Time) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE usuario_economia SET
+			battery = LEAST(battery + $2, $5), battery_recarga_em = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1 AND battery_recarga_em = $3
+	`
+
+	tag, err := config.DB.Exec(ctx, query, usuarioID, cargas, de, para, models.BatteryMax)
+	if err != nil {
+		return false, fmt.Errorf("erro ao recarregar battery: %v", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// InsertAIUso grava o consumo de uma chamada de IA
+func InsertAIUso(uso *models.AIUso) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO ai_uso (usuario_id, provider, model, prompt_tokens, completion_tokens, estimado)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := config.DB.QueryRow(ctx, query,
+		uso.UsuarioID, uso.Provider, uso.Model,
+		uso.PromptTokens, uso.CompletionTokens, uso.Estimado,
+	).Scan(&uso.ID, &uso.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir ai_uso: %v", err)
+	}
+
+	return nil
+}
+
+// GetAIUsoPorProvider agrega o consumo do usuário por provedor desde a data informada
+func GetAIUsoPorProvider(usuarioID int, desde time.Time) ([]models.AIUsoProvider, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT provider, COUNT(*), COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0)
+		FROM ai_uso
+		WHERE usuario_id = $1 AND created_at >= $2
+		GROUP BY provider
+		ORDER BY provider
+	`
+
+	rows, err := config.DB.Query(ctx, query, usuarioID, desde)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resultado := []models.AIUsoProvider{}
+	for rows.Next() {
+		var p models.AIUsoProvider
+		if err := rows.Scan(&p.Provider, &p.Requisicoes, &p.PromptTokens, &p.CompletionTokens); err != nil {
+			return nil, err
+		}
+		resultado = append(resultado, p)
+	}
+
+	return resultado, rows.Err()
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"testing"
+	"time"
+)
+
+const schemaUsuarioEconomia = `
+	CREATE TEMP TABLE usuario_economia (
+		id                 SERIAL PRIMARY KEY,
+		usuario_id         INTEGER NOT NULL UNIQUE,
+		tokens             INTEGER NOT NULL DEFAULT 0,
+		gemas              INTEGER NOT NULL DEFAULT 0,
+		battery            INTEGER NOT NULL DEFAULT 0,
+		battery_recarga_em TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		plano              VARCHAR(32) NOT NULL DEFAULT 'free',
+		updated_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+func inserirEconomia(t *testing.T, usuarioID, battery, tokens int) {
+	t.Helper()
+
+	query := `INSERT INTO usuario_economia (usuario_id, battery, tokens) VALUES ($1, $2, $3)`
+	if _, err := config.DB.Exec(context.Background(), query, usuarioID, battery, tokens); err != nil {
+		t.Fatalf("erro ao inserir economia: %v", err)
+	}
+}
+
+func conferirEconomia(t *testing.T, usuarioID, wantBattery, wantTokens int) {
+	t.Helper()
+
+	e, err := GetUsuarioEconomia(usuarioID)
+	if err != nil {
+		t.Fatalf("GetUsuarioEconomia() error = %v", err)
+	}
+	if e.Battery != wantBattery || e.Tokens != wantTokens {
+		t.Errorf("battery, tokens = %d, %d, want %d, %d", e.Battery, e.Tokens, wantBattery, wantTokens)
+	}
+}
+
+func TestDebitarEconomia(t *testing.T) {
+	bancoDeTeste(t, schemaUsuarioEconomia)
+	inserirEconomia(t, 1, 2, 100)
+
+	ok, err := DebitarEconomia(1, 1, 30)
+	if err != nil || !ok {
+		t.Fatalf("DebitarEconomia() = %v, %v, want true, nil", ok, err)
+	}
+	conferirEconomia(t, 1, 1, 70)
+
+	// Saldo insuficiente: nada muda
+	ok, err = DebitarEconomia(1, 2, 0)
+	if err != nil || ok {
+		t.Fatalf("DebitarEconomia() sem saldo = %v, %v, want false, nil", ok, err)
+	}
+	conferirEconomia(t, 1, 1, 70)
+}
+
+func TestCreditarEconomia(t *testing.T) {
+	bancoDeTeste(t, schemaUsuarioEconomia)
+	inserirEconomia(t, 1, models.BatteryMax-1, 10)
+
+	if err := CreditarEconomia(1, 1, 5); err != nil {
+		t.Fatalf("CreditarEconomia() error = %v", err)
+	}
+	conferirEconomia(t, 1, models.BatteryMax, 15)
+
+	// A bateria não passa do limite
+	if err := CreditarEconomia(1, 3, 0); err != nil {
+		t.Fatalf("CreditarEconomia() error = %v", err)
+	}
+	conferirEconomia(t, 1, models.BatteryMax, 15)
+}
+
+func TestRecarregarBateria(t *testing.T) {
+	bancoDeTeste(t, schemaUsuarioEconomia)
+	inserirEconomia(t, 1, models.BatteryMax-2, 0)
+
+	e, err := GetUsuarioEconomia(1)
+	if err != nil {
+		t.Fatalf("GetUsuarioEconomia() error = %v", err)
+	}
+	para := e.BatteryRecargaEm.Add(time.Hour)
+
+	ok, err := RecarregarBateria(1, 5, e.BatteryRecargaEm, para)
+	if err != nil || !ok {
+		t.Fatalf("RecarregarBateria() = %v, %v, want true, nil", ok, err)
+	}
+	conferirEconomia(t, 1, models.BatteryMax, 0)
+
+	// A contagem já mudou: uma segunda recarga com o valor antigo não altera nada
+	ok, err = RecarregarBateria(1, 1, e.BatteryRecargaEm, para.Add(time.Hour))
+	if err != nil || ok {
+		t.Fatalf("RecarregarBateria() repetida = %v, %v, want false, nil", ok, err)
+	}
+
+	e, err = GetUsuarioEconomia(1)
+	if err != nil {
+		t.Fatalf("GetUsuarioEconomia() error = %v", err)
+	}
+	if !e.BatteryRecargaEm.Equal(para) {
+		t.Errorf("BatteryRecargaEm = %v, want %v", e.BatteryRecargaEm, para)
+	}
+}
+
+func TestUpdatePlano(t *testing.T) {
+	bancoDeTeste(t, schemaUsuarioEconomia)
+	inserirEconomia(t, 1, 0, 0)
+
+	ok, err := UpdatePlano(1, "premium")
+	if err != nil || !ok {
+		t.Fatalf("UpdatePlano() = %v, %v, want true, nil", ok, err)
+	}
+
+	e, err := GetUsuarioEconomia(1)
+	if err != nil {
+		t.Fatalf("GetUsuarioEconomia() error = %v", err)
+	}
+	if e.Plano != "premium" {
+		t.Errorf("Plano = %q, want %q", e.Plano, "premium")
+	}
+
+	// Usuário sem economia: nada é alterado
+	if ok, err := UpdatePlano(2, "premium"); err != nil || ok {
+		t.Errorf("UpdatePlano() de usuário inexistente = %v, %v, want false, nil", ok, err)
+	}
+}
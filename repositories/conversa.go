@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+)
+
+// InsertConversa cria uma nova conversa para o usuário
+func InsertConversa(conversa *models.Conversa) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO conversa (usuario_id, titulo)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := config.DB.QueryRow(ctx, query, conversa.UsuarioID, conversa.Titulo).Scan(
+		&conversa.ID, &conversa.CreatedAt, &conversa.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir conversa: %v", err)
+	}
+
+	return nil
+}
+
+// GetConversasByUsuario retorna as conversas do usuário (mais recentes primeiro)
+func GetConversasByUsuario(usuarioID int) ([]models.Conversa, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, usuario_id, titulo, created_at, updated_at
+		FROM conversa
+		WHERE usuario_id = $1
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := config.DB.Query(ctx, query, usuarioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversas := []models.Conversa{}
+	for rows.Next() {
+		var c models.Conversa
+		if err := rows.Scan(&c.ID, &c.UsuarioID, &c.Titulo, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		conversas = append(conversas, c)
+	}
+
+	return conversas, rows.Err()
+}
+
+// GetConversa retorna uma conversa do usuário (nil se não existir ou for de outro usuário)
+func GetConversa(usuarioID, conversaID int) (*models.Conversa, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, usuario_id, titulo, created_at, updated_at
+		FROM conversa
+		WHERE id = $1 AND usuario_id = $2
+	`
+
+	var c models.Conversa
+	err := config.DB.QueryRow(ctx, query, conversaID, usuarioID).Scan(
+		&c.ID, &c.UsuarioID, &c.Titulo, &c.CreatedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// GetMensagensConversa retorna as últimas `limite` mensagens da conversa em ordem cronológica
+// (limite <= 0 retorna todas)
+func GetMensagensConversa(conversaID, limite int) ([]models.ConversaMensagem, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, conversa_id, role, content, provider, created_at
+		FROM (
+			SELECT id, conversa_id, role, content, provider, created_at
+			FROM conversa_mensagem
+			WHERE conversa_id = $1
+			ORDER BY id DESC
+			LIMIT NULLIF($2, 0)
+		) recentes
+		ORDER BY id ASC
+	`
+
+	if limite < 0 {
+		limite = 0
+	}
+
+	rows, err := config.DB.Query(ctx, query, conversaID, limite)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mensagens := []models.ConversaMensagem{}
+	for rows.Next() {
+		var m models.ConversaMensagem
+		if err := rows.Scan(&m.ID, &m.ConversaID, &m.Role, &m.Content, &m.Provider, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		mensagens = append(mensagens, m)
+	}
+
+	return mensagens, rows.Err()
+}
+
+// GetMensagemUsuario retorna uma mensagem de qualquer conversa do usuário
+func GetMensagemUsuario(usuarioID, mensagemID int) (*models.ConversaMensagem, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT m.id, m.conversa_id, m.role, m.content, m.provider, m.created_at
+		FROM conversa_mensagem m
+		JOIN conversa c ON c.id = m.conversa_id
+		WHERE m.id = $1 AND c.usuario_id = $2
+	`
+
+	var m models.ConversaMensagem
+	err := config.DB.QueryRow(ctx, query, mensagemID, usuarioID).Scan(
+		&m.ID, &m.ConversaID, &m.Role, &m.Content, &m.Provider, &m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// InsertMensagensConversa grava as mensagens da conversa e atualiza o updated_at (transação)
+func InsertMensagensConversa(conversaID int, mensagens ...*models.ConversaMensagem) error {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO conversa_mensagem (conversa_id, role, content, provider)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	for _, m := range mensagens {
+		m.ConversaID = conversaID
+		err = tx.QueryRow(ctx, query, conversaID, m.Role, m.Content, m.Provider).Scan(&m.ID, &m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("erro ao inserir conversa_mensagem: %v", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE conversa SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, conversaID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar conversa: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteConversa apaga a conversa do usuário (as mensagens caem em cascata)
+func DeleteConversa(usuarioID, conversaID int) (bool, error) {
+	ctx := context.Background()
+
+	tag, err := config.DB.Exec(ctx, `DELETE FROM conversa WHERE id = $1 AND usuario_id = $2`, conversaID, usuarioID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"lingobotAPI-GO/config"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// bancoDeTeste conecta config.DB ao banco de TEST_DATABASE_URL (o teste é pulado sem ele)
+// e cria as tabelas informadas como temporárias. Com uma única conexão no pool, as
+// tabelas temporárias escondem as reais e somem ao final do teste.
+func bancoDeTeste(t *testing.T, schema ...string) {
+	t.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL não definida")
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL inválida: %v", err)
+	}
+	poolConfig.MaxConns = 1
+
+	ctx := context.Background()
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		t.Fatalf("erro ao conectar ao banco de teste: %v", err)
+	}
+
+	for _, query := range schema {
+		if _, err := db.Exec(ctx, query); err != nil {
+			db.Close()
+			t.Fatalf("erro ao criar tabela de teste: %v", err)
+		}
+	}
+
+	anterior := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = anterior
+		db.Close()
+	})
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InsertNivelamento cria um teste de nivelamento, abandonando o teste em andamento do usuário (transação)
+func InsertNivelamento(n *models.Nivelamento) error {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE nivelamento SET status = $2
+		WHERE usuario_id = $1 AND status = $3
+	`, n.UsuarioID, models.NivelamentoAbandonado, models.NivelamentoEmAndamento)
+	if err != nil {
+		return fmt.Errorf("erro ao abandonar nivelamento: %v", err)
+	}
+
+	query := `
+		INSERT INTO nivelamento (usuario_id, status, nivel_atual, total_questoes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(ctx, query, n.UsuarioID, n.Status, n.NivelAtual, n.TotalQuestoes).Scan(&n.ID, &n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir nivelamento: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	return nil
+}
+
+// GetNivelamento retorna um teste do usuário com o número de questões respondidas
+func GetNivelamento(usuarioID, nivelamentoID int) (*models.Nivelamento, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT n.id, n.usuario_id, n.status, n.nivel_atual, n.total_questoes, n.cefr,
+			n.created_at, n.concluido_em,
+			(SELECT COUNT(*) FROM nivelamento_questao q WHERE q.nivelamento_id = n.id AND q.respondida_em IS NOT NULL)
+		FROM nivelamento n
+		WHERE n.id = $1 AND n.usuario_id = $2
+	`
+
+	var n models.Nivelamento
+	err := config.DB.QueryRow(ctx, query, nivelamentoID, usuarioID).Scan(
+		&n.ID, &n.UsuarioID, &n.Status, &n.NivelAtual, &n.TotalQuestoes, &n.Cefr,
+		&n.CreatedAt, &n.ConcluidoEm, &n.Respondidas,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+// GetQuestoesNivelamento retorna as questões do teste em ordem
+func GetQuestoesNivelamento(nivelamentoID int) ([]models.NivelamentoQuestao, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, nivelamento_id, ordem, cefr, tipo, enunciado, opcoes, resposta_correta, explicacao,
+			resposta, correta, feedback, created_at, respondida_em
+		FROM nivelamento_questao
+		WHERE nivelamento_id = $1
+		ORDER BY ordem
+	`
+
+	rows, err := config.DB.Query(ctx, query, nivelamentoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questoes := []models.NivelamentoQuestao{}
+	for rows.Next() {
+		var q models.NivelamentoQuestao
+		var opcoesJSON []byte
+		err := rows.Scan(
+			&q.ID, &q.NivelamentoID, &q.Ordem, &q.Cefr, &q.Tipo, &q.Enunciado, &opcoesJSON, &q.RespostaCorreta, &q.Explicacao,
+			&q.Resposta, &q.Correta, &q.Feedback, &q.CreatedAt, &q.RespondidaEm,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if opcoesJSON != nil {
+			if err := utils.Unmarshal(opcoesJSON, &q.Opcoes); err != nil {
+				return nil, fmt.Errorf("erro ao deserializar opcoes: %v", err)
+			}
+		}
+		questoes = append(questoes, q)
+	}
+
+	return questoes, rows.Err()
+}
+
+// InsertQuestaoNivelamento grava uma questão gerada para o teste.
+// Retorna false se outra requisição já gravou a questão dessa ordem (nada é alterado).
+func InsertQuestaoNivelamento(q *models.NivelamentoQuestao) (bool, error) {
+	ctx := context.Background()
+
+	var opcoesJSON []byte
+	if q.Opcoes != nil {
+		var err error
+		opcoesJSON, err = utils.Marshal(q.Opcoes)
+		if err != nil {
+			return false, fmt.Errorf("erro ao serializar opcoes: %v", err)
+		}
+	}
+
+	query := `
+		INSERT INTO nivelamento_questao (nivelamento_id, ordem, cefr, tipo, enunciado, opcoes, resposta_correta, explicacao)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (nivelamento_id, ordem) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err := config.DB.QueryRow(ctx, query,
+		q.NivelamentoID, q.Ordem, q.Cefr, q.Tipo, q.Enunciado, opcoesJSON, q.RespostaCorreta, q.Explicacao,
+	).Scan(&q.ID, &q.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir nivelamento_questao: %v", err)
+	}
+
+	return true, nil
+}
+
+// ResponderQuestaoNivelamento grava a resposta corrigida e o nível da próxima questão (transação).
+// Retorna false se a questão já tinha sido respondida (nada é alterado).
+func ResponderQuestaoNivelamento(q *models.NivelamentoQuestao, nivelAtual string) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE nivelamento_questao SET
+			resposta = $2, correta = $3, feedback = $4, respondida_em = CURRENT_TIMESTAMP
+		WHERE id = $1 AND respondida_em IS NULL
+		RETURNING respondida_em
+	`
+
+	err = tx.QueryRow(ctx, query, q.ID, q.Resposta, q.Correta, q.Feedback).Scan(&q.RespondidaEm)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao atualizar nivelamento_questao: %v", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE nivelamento SET nivel_atual = $2 WHERE id = $1`, q.NivelamentoID, nivelAtual)
+	if err != nil {
+		return false, fmt.Errorf("erro ao atualizar nivelamento: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	return true, nil
+}
+
+// ConcluirNivelamento grava o resultado do teste e atualiza o nível CEFR e a dificuldade do usuário (transação)
+func ConcluirNivelamento(n *models.Nivelamento, difficulty string) error {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE nivelamento SET
+			status = $2, cefr = $3, concluido_em = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING concluido_em
+	`
+
+	if err := tx.QueryRow(ctx, query, n.ID, models.NivelamentoConcluido, n.Cefr).Scan(&n.ConcluidoEm); err != nil {
+		return fmt.Errorf("erro ao concluir nivelamento: %v", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE usuario_progresso SET
+			cefr = $2, difficulty = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1
+	`, n.UsuarioID, n.Cefr, difficulty)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar usuario_progresso: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	n.Status = models.NivelamentoConcluido
+	return nil
+}
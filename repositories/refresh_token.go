@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InsertRefreshToken registra um refresh token emitido
+func InsertRefreshToken(rt *models.RefreshToken) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO refresh_token (jti, familia, usuario_id, expira_em)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	err := config.DB.QueryRow(ctx, query, rt.JTI, rt.Familia, rt.UsuarioID, rt.ExpiraEm).Scan(&rt.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir refresh_token: %v", err)
+	}
+
+	return nil
+}
+
+// GetRefreshToken retorna um refresh token pelo JTI
+func GetRefreshToken(jti string) (*models.RefreshToken, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT jti, familia, usuario_id, expira_em, usado_em, revogado_em, created_at
+		FROM refresh_token
+		WHERE jti = $1
+	`
+
+	var rt models.RefreshToken
+	err := config.DB.QueryRow(ctx, query, jti).Scan(
+		&rt.JTI, &rt.Familia, &rt.UsuarioID, &rt.ExpiraEm, &rt.UsadoEm, &rt.RevogadoEm, &rt.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rt, nil
+}
+
+// RotacionarRefreshToken marca o token atual como usado e registra o novo da mesma família (transação).
+// Retorna false se o token atual não pode ser usado (já usado, revogado, expirado ou de outro usuário).
+func RotacionarRefreshToken(jti string, usuarioID int, novo *models.RefreshToken) (bool, error) {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE refresh_token SET usado_em = CURRENT_TIMESTAMP
+		WHERE jti = $1 AND usuario_id = $2 AND familia = $3
+			AND usado_em IS NULL AND revogado_em IS NULL AND expira_em > CURRENT_TIMESTAMP
+		RETURNING jti
+	`
+
+	var usado string
+	err = tx.QueryRow(ctx, query, jti, usuarioID, novo.Familia).Scan(&usado)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao usar refresh_token: %v", err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refresh_token (jti, familia, usuario_id, expira_em)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, novo.JTI, novo.Familia, novo.UsuarioID, novo.ExpiraEm).Scan(&novo.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir refresh_token: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	return true, nil
+}
+
+// RevogarFamiliaRefreshToken revoga todos os refresh tokens ainda válidos da família
+func RevogarFamiliaRefreshToken(familia string) (int64, error) {
+	ctx := context.Background()
+
+	tag, err := config.DB.Exec(ctx, `
+		UPDATE refresh_token SET revogado_em = CURRENT_TIMESTAMP
+		WHERE familia = $1 AND revogado_em IS NULL
+	`, familia)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao revogar família de refresh_token: %v", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
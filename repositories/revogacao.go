@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InsertTokenRevogado revoga um token pelo JTI até a expiração dele.
+// Aproveita para apagar as revogações de tokens que já expiraram.
+func InsertTokenRevogado(jti string, usuarioID int, expiraEm time.Time) error {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO token_revogado (jti, usuario_id, expira_em)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+
+	if _, err := config.DB.Exec(ctx, query, jti, usuarioID, expiraEm); err != nil {
+		return fmt.Errorf("erro ao inserir token_revogado: %v", err)
+	}
+
+	if _, err := config.DB.Exec(ctx, `DELETE FROM token_revogado WHERE expira_em < CURRENT_TIMESTAMP`); err != nil {
+		return fmt.Errorf("erro ao limpar token_revogado: %v", err)
+	}
+
+	return nil
+}
+
+// TokenRevogadoExiste informa se o JTI foi revogado
+func TokenRevogadoExiste(jti string) (bool, error) {
+	ctx := context.Background()
+
+	var existe bool
+	err := config.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM token_revogado WHERE jti = $1)`, jti).Scan(&existe)
+	if err != nil {
+		return false, fmt.Errorf("erro ao consultar token_revogado: %v", err)
+	}
+
+	return existe, nil
+}
+
+// GetRevogadoAntes retorna o instante até o qual os tokens do usuário foram revogados (nil se nunca)
+func GetRevogadoAntes(usuarioID int) (*time.Time, error) {
+	ctx := context.Background()
+
+	var antes time.Time
+	err := config.DB.QueryRow(ctx, `SELECT revogado_antes FROM usuario_revogacao WHERE usuario_id = $1`, usuarioID).Scan(&antes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar usuario_revogacao: %v", err)
+	}
+
+	return &antes, nil
+}
+
+// RevogarTokensUsuario revoga os tokens do usuário emitidos até `antes`, incluindo os refresh
+// tokens (transação). Nunca recua um corte anterior; retorna o corte que ficou valendo.
+func RevogarTokensUsuario(usuarioID int, antes time.Time) (time.Time, error) {
+	ctx := context.Background()
+
+	tx, err := config.DB.Begin(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("erro ao iniciar transação: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO usuario_revogacao (usuario_id, revogado_antes)
+		VALUES ($1, $2)
+		ON CONFLICT (usuario_id) DO UPDATE SET
+			revogado_antes = GREATEST(usuario_revogacao.revogado_antes, EXCLUDED.revogado_antes),
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING revogado_antes
+	`
+
+	var corte time.Time
+	if err := tx.QueryRow(ctx, query, usuarioID, antes).Scan(&corte); err != nil {
+		return time.Time{}, fmt.Errorf("erro ao atualizar usuario_revogacao: %v", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE refresh_token SET revogado_em = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1 AND revogado_em IS NULL AND created_at <= $2
+	`, usuarioID, corte)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("erro ao revogar refresh_token: %v", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, fmt.Errorf("erro ao commitar transação: %v", err)
+	}
+
+	return corte, nil
+}
@@ -159,7 +159,7 @@ func GetAllUsuarios() ([]models.UsuarioResponse, error) {
 			u.id, u.nome, u.sobrenome, u.gender, u.data_nascimento, u.created_at,
 			ue.id, ue.usuario_id, ue.tokens, ue.gemas, ue.battery, ue.plano, ue.updated_at,
 			up.id, up.usuario_id, up.lingo_exp, up.level, up.listening, up.writing,
-			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.updated_at,
+			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.cefr, up.updated_at,
 			us.id, us.usuario_id, us.referal_code, us.invited_by, us.updated_at,
 			uc.id, uc.usuario_id, uc.items, uc.daily_missions, uc.achievements, uc.updated_at
 		FROM usuario u
@@ -191,7 +191,7 @@ func GetAllUsuarios() ([]models.UsuarioResponse, error) {
 			&economia.Battery, &economia.Plano, &economia.UpdatedAt,
 			&progresso.ID, &progresso.UsuarioID, &progresso.LingoEXP, &progresso.Level,
 			&progresso.Listening, &progresso.Writing, &progresso.Reading, &progresso.Speaking,
-			&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.UpdatedAt,
+			&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.Cefr, &progresso.UpdatedAt,
 			&social.ID, &social.UsuarioID, &social.ReferalCode, &social.InvitedBy, &social.UpdatedAt,
 			&conteudo.ID, &conteudo.UsuarioID, &itemsJSON, &dailyJSON, &achievementsJSON, &conteudo.UpdatedAt,
 		)
@@ -232,7 +232,7 @@ func GetUsuarioByEmail(email string) (*models.UsuarioCompleto, error) {
 			useg.id, useg.usuario_id, useg.otp_code, useg.otp_ativo, useg.updated_at,
 			ue.id, ue.usuario_id, ue.tokens, ue.gemas, ue.battery, ue.plano, ue.updated_at,
 			up.id, up.usuario_id, up.lingo_exp, up.level, up.listening, up.writing,
-			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.updated_at,
+			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.cefr, up.updated_at,
 			us.id, us.usuario_id, us.referal_code, us.invited_by, us.updated_at,
 			uc.id, uc.usuario_id, uc.items, uc.daily_missions, uc.achievements, uc.updated_at
 		FROM usuario u
@@ -260,7 +260,7 @@ func GetUsuarioByEmail(email string) (*models.UsuarioCompleto, error) {
 		&economia.Battery, &economia.Plano, &economia.UpdatedAt,
 		&progresso.ID, &progresso.UsuarioID, &progresso.LingoEXP, &progresso.Level,
 		&progresso.Listening, &progresso.Writing, &progresso.Reading, &progresso.Speaking,
-		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.UpdatedAt,
+		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.Cefr, &progresso.UpdatedAt,
 		&social.ID, &social.UsuarioID, &social.ReferalCode, &social.InvitedBy, &social.UpdatedAt,
 		&conteudo.ID, &conteudo.UsuarioID, &itemsJSON, &dailyJSON, &achievementsJSON, &conteudo.UpdatedAt,
 	)
@@ -299,7 +299,7 @@ func GetUsuarioByID(id int) (*models.UsuarioCompleto, error) {
 			useg.id, useg.usuario_id, useg.otp_code, useg.otp_ativo, useg.updated_at,
 			ue.id, ue.usuario_id, ue.tokens, ue.gemas, ue.battery, ue.plano, ue.updated_at,
 			up.id, up.usuario_id, up.lingo_exp, up.level, up.listening, up.writing,
-			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.updated_at,
+			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.cefr, up.updated_at,
 			us.id, us.usuario_id, us.referal_code, us.invited_by, us.updated_at,
 			uc.id, uc.usuario_id, uc.items, uc.daily_missions, uc.achievements, uc.updated_at
 		FROM usuario u
@@ -327,7 +327,7 @@ func GetUsuarioByID(id int) (*models.UsuarioCompleto, error) {
 		&economia.Battery, &economia.Plano, &economia.UpdatedAt,
 		&progresso.ID, &progresso.UsuarioID, &progresso.LingoEXP, &progresso.Level,
 		&progresso.Listening, &progresso.Writing, &progresso.Reading, &progresso.Speaking,
-		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.UpdatedAt,
+		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.Cefr, &progresso.UpdatedAt,
 		&social.ID, &social.UsuarioID, &social.ReferalCode, &social.InvitedBy, &social.UpdatedAt,
 		&conteudo.ID, &conteudo.UsuarioID, &itemsJSON, &dailyJSON, &achievementsJSON, &conteudo.UpdatedAt,
 	)
@@ -362,6 +362,18 @@ func UpdateUsuario(u *models.Usuario) error {
 	return nil
 }
 
+// UpdateSenha grava o novo hash de senha do usuário
+func UpdateSenha(usuarioID int, senhaHash string) error {
+	ctx := context.Background()
+
+	query := `UPDATE usuario SET password = $2 WHERE id = $1`
+	if _, err := config.DB.Exec(ctx, query, usuarioID, senhaHash); err != nil {
+		return fmt.Errorf("erro ao atualizar senha: %v", err)
+	}
+
+	return nil
+}
+
 // UpdateUsuarioCompleto atualiza todas as tabelas do usuário
 func UpdateUsuarioCompleto(uc *models.UsuarioCompleto) error {
 	ctx := context.Background()
@@ -392,17 +404,14 @@ func UpdateUsuarioCompleto(uc *models.UsuarioCompleto) error {
 		return fmt.Errorf("erro ao atualizar usuario: %v", err)
 	}
 
-	// 2. Atualiza tabela usuario_economia
+	// 2. Atualiza tabela usuario_economia (tokens, battery e plano só mudam no servidor)
 	queryEconomia := `
 		UPDATE usuario_economia SET
-			tokens = $1, gemas = $2, battery = $3, plano = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE usuario_id = $5
+			gemas = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $2
 	`
 	_, err = tx.Exec(ctx, queryEconomia,
-		uc.Economia.Tokens,
 		uc.Economia.Gemas,
-		uc.Economia.Battery,
-		uc.Economia.Plano,
 		uc.Usuario.ID,
 	)
 	if err != nil {
@@ -39,7 +39,7 @@ func GetUsuarioContent(usuarioID int) (*models.UsuarioContentResponse, error) {
 		SELECT 
 			ue.id, ue.usuario_id, ue.tokens, ue.gemas, ue.battery, ue.plano, ue.updated_at,
 			up.id, up.usuario_id, up.lingo_exp, up.level, up.listening, up.writing,
-			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.updated_at,
+			up.reading, up.speaking, up.ranking, up.difficulty, up.learning, up.cefr, up.updated_at,
 			uc.id, uc.usuario_id, uc.items, uc.daily_missions, uc.achievements, uc.updated_at
 		FROM usuario_economia ue
 		LEFT JOIN usuario_progresso up ON ue.usuario_id = up.usuario_id
@@ -57,7 +57,7 @@ func GetUsuarioContent(usuarioID int) (*models.UsuarioContentResponse, error) {
 		&economia.Battery, &economia.Plano, &economia.UpdatedAt,
 		&progresso.ID, &progresso.UsuarioID, &progresso.LingoEXP, &progresso.Level,
 		&progresso.Listening, &progresso.Writing, &progresso.Reading, &progresso.Speaking,
-		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.UpdatedAt,
+		&progresso.Ranking, &progresso.Difficulty, &progresso.Learning, &progresso.Cefr, &progresso.UpdatedAt,
 		&conteudo.ID, &conteudo.UsuarioID, &itemsJSON, &dailyJSON, &achievementsJSON, &conteudo.UpdatedAt,
 	)
 
@@ -128,3 +128,47 @@ func GetUsuarioSecurity(usuarioID int) (*models.UsuarioSeguranca, error) {
 
 	return &seg, nil
 }
+
+// GetUsuarioProgresso retorna o progresso do usuário (XP, level, skills, idioma e dificuldade)
+func GetUsuarioProgresso(usuarioID int) (*models.UsuarioProgresso, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT id, usuario_id, lingo_exp, level, listening, writing,
+			reading, speaking, ranking, difficulty, learning, cefr, updated_at
+		FROM usuario_progresso
+		WHERE usuario_id = $1
+	`
+
+	var p models.UsuarioProgresso
+	err := config.DB.QueryRow(ctx, query, usuarioID).Scan(
+		&p.ID, &p.UsuarioID, &p.LingoEXP, &p.Level,
+		&p.Listening, &p.Writing, &p.Reading, &p.Speaking,
+		&p.Ranking, &p.Difficulty, &p.Learning, &p.Cefr, &p.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// IncrementarWriting soma pontos à skill Writing do usuário e retorna o valor atualizado
+func IncrementarWriting(usuarioID, pontos int) (int, error) {
+	ctx := context.Background()
+
+	query := `
+		UPDATE usuario_progresso SET
+			writing = writing + $2, updated_at = CURRENT_TIMESTAMP
+		WHERE usuario_id = $1
+		RETURNING writing
+	`
+
+	var writing int
+	if err := config.DB.QueryRow(ctx, query, usuarioID, pontos).Scan(&writing); err != nil {
+		return 0, fmt.Errorf("erro ao atualizar writing: %v", err)
+	}
+
+	return writing, nil
+}
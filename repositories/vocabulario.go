@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const vocabularioColunas = `
+	id, usuario_id, idioma, palavra, traducao, exemplo, origem,
+	facilidade, intervalo, repeticoes, proxima_revisao, ultima_revisao, created_at
+`
+
+// scanVocabulario lê uma linha com as colunas de vocabularioColunas
+func scanVocabulario(row pgx.Row, v *models.Vocabulario) error {
+	return row.Scan(
+		&v.ID, &v.UsuarioID, &v.Idioma, &v.Palavra, &v.Traducao, &v.Exemplo, &v.Origem,
+		&v.Facilidade, &v.Intervalo, &v.Repeticoes, &v.ProximaRevisao, &v.UltimaRevisao, &v.CreatedAt,
+	)
+}
+
+// InsertVocabulario adiciona a palavra ao baralho, já disponível para revisão.
+// Retorna false se a palavra já está no baralho do usuário nesse idioma (nada é alterado).
+func InsertVocabulario(v *models.Vocabulario) (bool, error) {
+	ctx := context.Background()
+
+	query := `
+		INSERT INTO vocabulario (usuario_id, idioma, palavra, traducao, exemplo, origem)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (usuario_id, idioma, LOWER(palavra)) DO NOTHING
+		RETURNING ` + vocabularioColunas
+
+	row := config.DB.QueryRow(ctx, query, v.UsuarioID, v.Idioma, v.Palavra, v.Traducao, v.Exemplo, v.Origem)
+	err := scanVocabulario(row, v)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("erro ao inserir vocabulario: %v", err)
+	}
+
+	return true, nil
+}
+
+// GetVocabulario retorna um cartão do usuário
+func GetVocabulario(usuarioID, vocabularioID int) (*models.Vocabulario, error) {
+	ctx := context.Background()
+
+	query := `SELECT ` + vocabularioColunas + ` FROM vocabulario WHERE id = $1 AND usuario_id = $2`
+
+	var v models.Vocabulario
+	if err := scanVocabulario(config.DB.QueryRow(ctx, query, vocabularioID, usuarioID), &v); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// GetVocabularioByUsuario retorna o baralho do usuário no idioma (ordem alfabética)
+func GetVocabularioByUsuario(usuarioID int, idioma string) ([]models.Vocabulario, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + vocabularioColunas + `
+		FROM vocabulario
+		WHERE usuario_id = $1 AND idioma = $2
+		ORDER BY LOWER(palavra)
+	`
+
+	return queryVocabulario(ctx, query, usuarioID, idioma)
+}
+
+// GetVocabularioPendente retorna até `limite` cartões com revisão vencida (os mais atrasados primeiro)
+func GetVocabularioPendente(usuarioID int, idioma string, limite int) ([]models.Vocabulario, error) {
+	ctx := context.Background()
+
+	query := `
+		SELECT ` + vocabularioColunas + `
+		FROM vocabulario
+		WHERE usuario_id = $1 AND idioma = $2 AND proxima_revisao <= CURRENT_TIMESTAMP
+		ORDER BY proxima_revisao
+		LIMIT $3
+	`
+
+	return queryVocabulario(ctx, query, usuarioID, idioma, limite)
+}
+
+func queryVocabulario(ctx context.Context, query string, args ...interface{}) ([]models.Vocabulario, error) {
+	rows, err := config.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cartoes := []models.Vocabulario{}
+	for rows.Next() {
+		var v models.Vocabulario
+		if err := scanVocabulario(rows, &v); err != nil {
+			return nil, err
+		}
+		cartoes = append(cartoes, v)
+	}
+
+	return cartoes, rows.Err()
+}
+
+// UpdateRevisaoVocabulario grava o novo agendamento do cartão depois de uma revisão
+func UpdateRevisaoVocabulario(v *models.Vocabulario) error {
+	ctx := context.Background()
+
+	query := `
+		UPDATE vocabulario SET
+			facilidade = $2, intervalo = $3, repeticoes = $4,
+			proxima_revisao = $5, ultima_revisao = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING ultima_revisao
+	`
+
+	err := config.DB.QueryRow(ctx, query, v.ID, v.Facilidade, v.Intervalo, v.Repeticoes, v.ProximaRevisao).Scan(&v.UltimaRevisao)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar vocabulario: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteVocabulario remove o cartão do baralho do usuário
+func DeleteVocabulario(usuarioID, vocabularioID int) (bool, error) {
+	ctx := context.Background()
+
+	tag, err := config.DB.Exec(ctx, `DELETE FROM vocabulario WHERE id = $1 AND usuario_id = $2`, vocabularioID, usuarioID)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"lingobotAPI-GO/controllers"
 	"lingobotAPI-GO/middlewares"
+	"lingobotAPI-GO/services"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,14 +24,17 @@ func RegisterRoutes(router *gin.Engine) {
 	router.GET("/health", func(c *gin.Context) {
 		uptime := formatDuration(time.Since(startTime))
 		c.JSON(200, gin.H{
-			"status":  "ok",
-			"uptime":  uptime,
-			"version": "1.0.0",
+			"status":    "ok",
+			"uptime":    uptime,
+			"version":   "1.0.0",
+			"providers": services.ProviderHealth(),
 		})
 	})
 
 	router.POST("/usuarios", controllers.CriarUsuario)
 	router.POST("/login", controllers.Login)
+	router.POST("/token/refresh", controllers.RefreshToken) // Rotaciona o refresh token (reuso revoga a família)
+	router.GET("/.well-known/jwks.json", controllers.JWKS)  // Chaves públicas para outros serviços validarem os tokens
 
 	// Rotas protegidas (com autenticação JWT)
 	protected := router.Group("/")
@@ -39,6 +43,11 @@ func RegisterRoutes(router *gin.Engine) {
 		// Usuários - Lista geral
 		protected.GET("/usuarios", controllers.GetUsuarios)
 		protected.POST("/update-user-data", controllers.UpdateUserData)
+		protected.POST("/logout", controllers.Logout)            // Revoga o token atual (e o refresh_token enviado)
+		protected.POST("/logout/todos", controllers.LogoutTodos) // Revoga os tokens emitidos até "antes" (padrão: agora)
+
+		// Senha - exige token fresh (login com senha) e a senha atual
+		protected.PUT("/usuarios/senha", middlewares.FreshTokenMiddleware(), controllers.AlterarSenha)
 
 		// Usuários - Dados específicos por ID
 		protected.GET("/usuarios/profile/:id", controllers.GetUsuarioProfile)                  // Perfil básico
@@ -46,16 +55,50 @@ func RegisterRoutes(router *gin.Engine) {
 		protected.GET("/usuarios/social/:id", controllers.GetUsuarioSocial)                    // Referal code, invited_by
 		protected.GET("/usuarios/security/:id", controllers.GetUsuarioSecurity)                // OTP (admin only)
 
-		// IA - Todas as rotas protegidas
-		protected.POST("/ai/gemini", controllers.AIGemini)
-		protected.POST("/ai/cohere", controllers.AICohere)
-		protected.POST("/ai/mistral", controllers.AIMistral)
-		protected.POST("/ai/groq", controllers.AIGroq)
-		protected.POST("/ai/openrouter", controllers.AIOpenRouter)
-		protected.POST("/ai/benchmark", controllers.AIBenchmark)
+		// IA - Todas as rotas protegidas; as que chamam provedores são cobradas da battery/tokens
+		aiQuota := middlewares.AIQuotaMiddleware()
+		protected.GET("/ai/personas", controllers.AIPersonas)                          // Personas do tutor (campo "persona")
+		protected.GET("/ai/models", controllers.AIModels)                              // Catálogo de modelos (campo "model")
+		protected.GET("/ai/usage", controllers.AIUsage)                                // Saldo e consumo de IA do usuário
+		protected.GET("/ai/benchmark/leaderboard", controllers.AIBenchmarkLeaderboard) // p50/p95 e taxa de sucesso por provedor
+		protected.POST("/ai/gemini", aiQuota, controllers.AIGemini)                    // Fallback entre todos os provedores
+		protected.POST("/ai/stream", aiQuota, controllers.AIStream)                    // Fallback com resposta em SSE
+		protected.POST("/ai/structured", aiQuota, controllers.AIStructured)            // JSON validado (schema ou exercise_type)
+		protected.POST("/ai/grammar", aiQuota, controllers.AIGrammar)                  // Correção com edições e categorias (soma Writing)
+		protected.POST("/ai/benchmark", aiQuota, controllers.AIBenchmark)
+		protected.POST("/ai/:provider", aiQuota, controllers.AIProvider) // Provedor específico (cohere, mistral, groq...)
+
+		// Conversas com o tutor (histórico no servidor)
+		protected.POST("/conversas", controllers.CriarConversa)
+		protected.GET("/conversas", controllers.GetConversas)
+		protected.GET("/conversas/:id", controllers.GetConversa)
+		protected.POST("/conversas/:id/mensagens", aiQuota, controllers.EnviarMensagem)
+		protected.DELETE("/conversas/:id", controllers.ApagarConversa)
+
+		// Teste de nivelamento CEFR (cobrado uma vez, ao iniciar)
+		protected.POST("/nivelamento", aiQuota, controllers.IniciarNivelamento)
+		protected.GET("/nivelamento/:id", controllers.GetNivelamento)
+		protected.GET("/nivelamento/:id/questao", controllers.ProximaQuestaoNivelamento)
+		protected.POST("/nivelamento/:id/respostas", controllers.ResponderNivelamento)
+
+		// Vocabulário com revisão espaçada (SM-2) no idioma estudado
+		protected.GET("/vocabulario", controllers.GetVocabulario)
+		protected.POST("/vocabulario", controllers.AdicionarVocabulario)
+		protected.POST("/vocabulario/extrair", aiQuota, controllers.ExtrairVocabulario) // Palavras de um texto ou mensagem (mensagem_id)
+		protected.GET("/vocabulario/revisao", controllers.GetVocabularioPendente)
+		protected.POST("/vocabulario/:id/revisao", controllers.RevisarVocabulario)
+		protected.DELETE("/vocabulario/:id", controllers.ApagarVocabulario)
 
 		// Mídia - TTS e Transcrição
 		protected.POST("/tts", controllers.TTS)
 		protected.POST("/transcribe", controllers.TranscribeAudio)
 	}
+
+	// Rotas de administração (JWT + usuário em ADMIN_USER_IDS)
+	admin := router.Group("/admin")
+	admin.Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	{
+		admin.GET("/ai/keys", controllers.AIKeyPools)                     // Uso e suspensão de cada chave de API
+		admin.PUT("/usuarios/:id/plano", controllers.DefinirPlanoUsuario) // Plano do usuário (libera os modelos premium)
+	}
 }
@@ -1,333 +1,248 @@
 package services
 
 import (
-	"LingobotAPI-GO/utils"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"log"
 	"net/http"
-	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
-// CallGemini chama a API do Google Gemini
-func CallGemini(text string) (string, error) {
-	apiKey := os.Getenv("GOOGLE_GEMINI_API_KEY1")
-	if apiKey == "" {
-		return "", errors.New("gemini API key not configured")
-	}
+// Provider é a interface comum a todos os fornecedores de IA.
+// Para adicionar um novo fornecedor basta criar um arquivo ai_<nome>.go
+// com a implementação e registrá-la no init() via RegisterProvider.
+type Provider interface {
+	// Name retorna o identificador do provedor (usado nas rotas /ai/:provider)
+	Name() string
+	// Complete envia o pedido ao provedor e devolve a resposta gerada
+	Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error)
+}
 
-	url := fmt.Sprintf("https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent?key=%s", apiKey)
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
 
-	payload := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]string{
-					{"text": text},
-				},
-			},
-		},
-	}
+// defaultProviderOrder define a ordem de fallback quando nenhuma ordem é configurada.
+// Provedores registrados que não estão na lista entram no final, em ordem alfabética.
+var defaultProviderOrder = []string{"gemini", "mistral", "cohere", "groq", "openrouter"}
+
+var (
+	// ErrProviderNotFound é retornado quando o provedor pedido não está registrado
+	ErrProviderNotFound = errors.New("AI provider not found")
+	// ErrProviderDisabled é retornado quando o provedor foi desabilitado na configuração
+	ErrProviderDisabled = errors.New("AI provider disabled")
+	// ErrNoProviderAvailable é retornado quando a cadeia de fallback fica vazia
+	ErrNoProviderAvailable = errors.New("no AI provider available")
+)
 
-	jsonData, _ := utils.Marshal(payload)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+// aiHTTPClient é o cliente HTTP compartilhado pelas chamadas aos provedores
+var aiHTTPClient = &http.Client{Timeout: 30 * time.Second}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("gemini API returned status %d", resp.StatusCode)
-	}
+// RegisterProvider adiciona um provedor ao registro
+func RegisterProvider(p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
 
-	body, _ := io.ReadAll(resp.Body)
-	var result map[string]interface{}
-	utils.Unmarshal(body, &result)
-
-	// Navega pela estrutura de resposta do Gemini
-	if candidates, ok := result["candidates"].([]interface{}); ok && len(candidates) > 0 {
-		if candidate, ok := candidates[0].(map[string]interface{}); ok {
-			if content, ok := candidate["content"].(map[string]interface{}); ok {
-				if parts, ok := content["parts"].([]interface{}); ok && len(parts) > 0 {
-					if part, ok := parts[0].(map[string]interface{}); ok {
-						if textContent, ok := part["text"].(string); ok {
-							return textContent, nil
-						}
-					}
-				}
-			}
-		}
+	if _, exists := providers[p.Name()]; exists {
+		panic(fmt.Sprintf("AI provider %q registrado duas vezes", p.Name()))
 	}
-
-	return "", errors.New("no text found in Gemini response")
+	providers[p.Name()] = p
 }
 
-// CallMistral chama a API do Mistral com retry
-func CallMistral(text string) (string, error) {
-	apiKey := os.Getenv("MISTRAL_KEY")
-	if apiKey == "" {
-		return "", errors.New("mistral API key not configured")
-	}
+// GetProvider retorna o provedor registrado com o nome informado
+func GetProvider(name string) (Provider, error) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
 
-	url := "https://api.mistral.ai/v1/chat/completions"
-	maxRetries := 3
-
-	payload := map[string]interface{}{
-		"model": "mistral-tiny",
-		"messages": []map[string]string{
-			{"role": "user", "content": text},
-		},
-		"temperature": 0.7,
-		"max_tokens":  2000,
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
 	}
+	return p, nil
+}
 
-	jsonData, _ := utils.Marshal(payload)
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-		req.Header.Set("Content-Type", "application/json")
-
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			if attempt < maxRetries-1 {
-				time.Sleep(time.Duration(1<<uint(attempt)) * time.Second)
-				continue
-			}
-			return "", err
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == 429 && attempt < maxRetries-1 {
-			time.Sleep(time.Duration(1<<uint(attempt)) * time.Second)
-			continue
-		}
+// ListProviders retorna todos os provedores registrados na ordem de fallback
+func ListProviders() []Provider {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
 
-		if resp.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("mistral API returned status %d", resp.StatusCode)
-		}
+	result := make([]Provider, 0, len(providers))
+	seen := make(map[string]bool, len(providers))
 
-		body, _ := io.ReadAll(resp.Body)
-		var result map[string]interface{}
-		utils.Unmarshal(body, &result)
-
-		if choices, ok := result["choices"].([]interface{}); ok && len(choices) > 0 {
-			if choice, ok := choices[0].(map[string]interface{}); ok {
-				if message, ok := choice["message"].(map[string]interface{}); ok {
-					if content, ok := message["content"].(string); ok {
-						return content, nil
-					}
-				}
-			}
+	for _, name := range defaultProviderOrder {
+		if p, ok := providers[name]; ok {
+			result = append(result, p)
+			seen[name] = true
 		}
-
-		return "", errors.New("no text found in Mistral response")
 	}
 
-	return "", errors.New("mistral request failed after retries")
-}
-
-// CallCohere chama a API do Cohere
-func CallCohere(text string) (string, error) {
-	apiKey := os.Getenv("COHERE_KEY")
-	if apiKey == "" {
-		return "", errors.New("cohere API key not configured")
+	var extras []string
+	for name := range providers {
+		if !seen[name] {
+			extras = append(extras, name)
+		}
 	}
-
-	url := "https://api.cohere.ai/v1/chat"
-
-	payload := map[string]interface{}{
-		"message":     text,
-		"model":       "command-r",
-		"temperature": 0.7,
-		"max_tokens":  1000,
+	sort.Strings(extras)
+	for _, name := range extras {
+		result = append(result, providers[name])
 	}
 
-	jsonData, _ := utils.Marshal(payload)
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	return result
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// CallProvider chama um único provedor pelo nome, sem fallback
+func CallProvider(ctx context.Context, name string, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	p, err := GetProvider(name)
 	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("cohere API returned status %d", resp.StatusCode)
+		return nil, err
 	}
 
-	body, _ := io.ReadAll(resp.Body)
-	var result map[string]interface{}
-	utils.Unmarshal(body, &result)
-
-	if textContent, ok := result["text"].(string); ok {
-		return textContent, nil
+	cfg := config.GetAIConfig().Provider(name)
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("%w: %s", ErrProviderDisabled, name)
 	}
 
-	return "", errors.New("no text found in Cohere response")
+	return completeWithTimeout(ctx, p, cfg.Timeout, req)
 }
 
-// CallGroq chama a API do Groq (via OpenAI SDK format)
-func CallGroq(text string) (string, error) {
-	apiKey := os.Getenv("GROQ_KEY")
-	if apiKey == "" {
-		return "", errors.New("groq API key not configured")
-	}
-
-	url := "https://api.groq.com/openai/v1/chat/completions"
-
-	payload := map[string]interface{}{
-		"model": "meta-llama/llama-4-scout-17b-16e-instruct",
-		"messages": []map[string]string{
-			{"role": "user", "content": text},
-		},
-		"temperature": 0.7,
-	}
-
-	jsonData, _ := utils.Marshal(payload)
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// CallAIWithFallback tenta os provedores em ordem até obter sucesso.
+// Se requested for informado, apenas esses provedores são tentados (na ordem dada);
+// caso contrário, usa a ordem configurada em config.GetAIConfig.
+// Provedores desabilitados na configuração ou com o circuito aberto são ignorados.
+func CallAIWithFallback(ctx context.Context, req models.CompletionRequest, requested []string) (*models.CompletionResponse, error) {
+	chain, err := providerChain(requested)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("groq API returned status %d", resp.StatusCode)
-	}
+	cfg := config.GetAIConfig()
 
-	body, _ := io.ReadAll(resp.Body)
-	var result map[string]interface{}
-	utils.Unmarshal(body, &result)
+	var attempts []ProviderAttempt
+	for _, p := range chain {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
-	if choices, ok := result["choices"].([]interface{}); ok && len(choices) > 0 {
-		if choice, ok := choices[0].(map[string]interface{}); ok {
-			if message, ok := choice["message"].(map[string]interface{}); ok {
-				if content, ok := message["content"].(string); ok {
-					return content, nil
-				}
-			}
+		response, err := completeWithTimeout(ctx, p, cfg.Provider(p.Name()).Timeout, req)
+		if err == nil {
+			return response, nil
 		}
+		attempts = append(attempts, ProviderAttempt{Provider: p.Name(), Err: err})
 	}
 
-	return "", errors.New("no text found in Groq response")
+	// Com um único provedor devolve o erro original
+	return nil, fallbackError(attempts)
 }
 
-// CallOpenRouter chama a API do OpenRouter com fallback de modelos
-func CallOpenRouter(text string) (string, error) {
-	apiKey := os.Getenv("OPENROUTER_KEY")
-	if apiKey == "" {
-		return "", errors.New("openRouter API key not configured")
-	}
-
-	url := "https://openrouter.ai/api/v1/chat/completions"
-
-	modelsToTry := []string{
-		"qwen/qwen3-235b-a22b-07-25:free",
-		"meta-llama/llama-3.1-8b-instruct:free",
-		"microsoft/phi-3-mini-128k-instruct:free",
-		"google/gemma-2-9b-it:free",
-	}
-
-	for _, model := range modelsToTry {
-		payload := map[string]interface{}{
-			"model": model,
-			"messages": []map[string]string{
-				{"role": "user", "content": text},
-			},
-			"max_tokens":  1000,
-			"temperature": 0.7,
-		}
-
-		jsonData, _ := utils.Marshal(payload)
-		req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-		req.Header.Set("Authorization", "Bearer "+apiKey)
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("HTTP-Referer", "https://lingobot-api.onrender.com")
-		req.Header.Set("X-Title", "Go Gin OpenRouter App")
-
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			continue
+// providerChain monta a cadeia de fallback a partir do pedido ou da configuração
+func providerChain(requested []string) ([]Provider, error) {
+	cfg := config.GetAIConfig()
+
+	var candidates []Provider
+	switch {
+	case len(requested) > 0:
+		for _, name := range requested {
+			p, err := GetProvider(strings.ToLower(strings.TrimSpace(name)))
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, p)
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode == http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			var result map[string]interface{}
-			utils.Unmarshal(body, &result)
-
-			if choices, ok := result["choices"].([]interface{}); ok && len(choices) > 0 {
-				if choice, ok := choices[0].(map[string]interface{}); ok {
-					if message, ok := choice["message"].(map[string]interface{}); ok {
-						if content, ok := message["content"].(string); ok {
-							return content, nil
-						}
-					}
-				}
+	case len(cfg.Order) > 0:
+		for _, name := range cfg.Order {
+			p, err := GetProvider(name)
+			if err != nil {
+				log.Printf("⚠️  Aviso: provedor %q da configuração não está registrado", name)
+				continue
 			}
+			candidates = append(candidates, p)
 		}
+	default:
+		candidates = ListProviders()
+	}
 
-		if resp.StatusCode == 503 {
-			continue
+	chain := make([]Provider, 0, len(candidates))
+	for _, p := range candidates {
+		if cfg.Provider(p.Name()).Enabled {
+			chain = append(chain, p)
 		}
 	}
 
-	return "", errors.New("todos os modelos estão indisponíveis no momento")
-}
-
-// CallAIWithFallback tenta todas as IAs em ordem até obter sucesso
-func CallAIWithFallback(text string, forceMistral, forceCohere, forceGroq bool) (string, error) {
-	// Se forçar Mistral
-	if forceMistral {
-		return CallMistral(text)
+	if len(chain) == 0 {
+		return nil, ErrNoProviderAvailable
 	}
 
-	// Se forçar Cohere
-	if forceCohere {
-		return CallCohere(text)
-	}
+	return chain, nil
+}
 
-	// Se forçar Groq
-	if forceGroq {
-		return CallGroq(text)
+// completeWithTimeout chama o provedor através do circuit breaker,
+// aplicando o timeout configurado (0 = sem limite extra), e registra o consumo
+func completeWithTimeout(ctx context.Context, p Provider, timeout time.Duration, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	// Parâmetros fora dos limites do provedor não chegam a ele (nem contam no circuit breaker)
+	if err := validateGeneration(p.Name(), req.GenerationParams); err != nil {
+		return nil, err
 	}
 
-	// Fallback: tenta todas em ordem
-	// 1. Gemini
-	if response, err := CallGemini(text); err == nil {
-		return response, nil
+	response, err := callWithBreaker(ctx, p, func(ctx context.Context) (*models.CompletionResponse, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return p.Complete(ctx, req)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. Mistral
-	if response, err := CallMistral(text); err == nil {
-		return response, nil
-	}
+	RegistrarUsoIA(ctx, req, response)
+	return response, nil
+}
+
+// providerURL monta a URL de um endpoint do fornecedor a partir da URL base configurada
+func providerURL(name, path string) string {
+	return config.GetAIConfig().BaseURL(name) + path
+}
 
-	// 3. Cohere
-	if response, err := CallCohere(text); err == nil {
-		return response, nil
+// postJSON envia o payload como JSON para a URL e devolve status e corpo da resposta.
+// Falhas temporárias são repetidas com doWithRetry; o status devolvido é o da última tentativa.
+// O corpo é sempre lido e fechado aqui, para não vazar conexões.
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}) (int, []byte, error) {
+	jsonData, err := utils.Marshal(payload)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	// 4. Groq
-	if response, err := CallGroq(text); err == nil {
-		return response, nil
+	resp, err := doWithRetry(ctx, aiHTTPClient, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return 0, nil, err
 	}
+	defer resp.Body.Close()
 
-	// 5. OpenRouter
-	if response, err := CallOpenRouter(text); err == nil {
-		return response, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
 	}
 
-	return "", errors.New("all AI services failed")
-}
\ No newline at end of file
+	return resp.StatusCode, body, nil
+}
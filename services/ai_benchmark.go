@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// benchmarkDefaultTimeout vale para provedores sem timeout configurado
+const benchmarkDefaultTimeout = 60 * time.Second
+
+// RunBenchmark chama todos os provedores registrados ao mesmo tempo e mede cada um.
+// O benchmark ignora o circuit breaker (o objetivo é medir o provedor) e termina
+// assim que ctx é cancelado, por exemplo quando o cliente desconecta.
+// Os resultados são gravados em ai_benchmark para o leaderboard.
+func RunBenchmark(ctx context.Context, req models.CompletionRequest) models.BenchmarkResponse {
+	cfg := config.GetAIConfig()
+	providers := ListProviders()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(models.BenchmarkResponse, len(providers))
+
+	for _, p := range providers {
+		wg.Add(1)
+		go func(p Provider) {
+			defer wg.Done()
+
+			timeout := cfg.Provider(p.Name()).Timeout
+			if timeout <= 0 {
+				timeout = benchmarkDefaultTimeout
+			}
+
+			result := benchmarkProvider(ctx, p, timeout, req)
+
+			mu.Lock()
+			results[p.Name()] = result
+			mu.Unlock()
+		}(p)
+	}
+
+	wg.Wait()
+
+	salvarBenchmark(ctx, req.Prompt, results)
+	return results
+}
+
+// salvarBenchmark grava uma linha por provedor, todas com o mesmo run_id
+func salvarBenchmark(ctx context.Context, prompt string, results models.BenchmarkResponse) {
+	runID := uuid.NewString()
+
+	var usuarioID *int
+	if id, ok := usuarioIAFromContext(ctx); ok {
+		usuarioID = &id
+	}
+
+	resultados := make([]models.AIBenchmarkResultado, 0, len(results))
+	for provider, result := range results {
+		// Pedido inválido, conteúdo bloqueado e cancelamento pelo cliente não medem o provedor
+		switch result.ErrorCategory {
+		case ErrorCategoryInvalidRequest, ErrorCategoryContentBlocked, ErrorCategoryCanceled:
+			continue
+		}
+
+		r := models.AIBenchmarkResultado{
+			RunID:        runID,
+			UsuarioID:    usuarioID,
+			Prompt:       prompt,
+			Provider:     provider,
+			LatencyMs:    int(result.Time * 1000),
+			Success:      result.Error == "",
+			OutputLength: len([]rune(result.Response)),
+		}
+		if result.Model != "" {
+			model := result.Model
+			r.Model = &model
+		}
+		if result.ErrorCategory != "" {
+			category := result.ErrorCategory
+			r.ErrorCategory = &category
+		}
+		resultados = append(resultados, r)
+	}
+
+	if err := repositories.InsertAIBenchmark(resultados); err != nil {
+		log.Printf("❌ Erro ao gravar benchmark de IA: %v", err)
+	}
+}
+
+// BenchmarkLeaderboard retorna p50/p95 de latência e taxa de sucesso por provedor
+// nos benchmarks dos últimos `dias`, do melhor para o pior
+func BenchmarkLeaderboard(dias int) (*models.AILeaderboard, error) {
+	ate := time.Now().UTC() // created_at é TIMESTAMP em UTC
+	desde := ate.AddDate(0, 0, -dias)
+
+	providers, err := repositories.GetAIBenchmarkRanking(desde, ate)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico de benchmark: %v", err)
+	}
+
+	return &models.AILeaderboard{
+		Desde:     desde,
+		Ate:       ate,
+		Providers: providers,
+	}, nil
+}
+
+// benchmarkProvider mede uma única chamada ao provedor
+func benchmarkProvider(ctx context.Context, p Provider, timeout time.Duration, req models.CompletionRequest) models.AIResponse {
+	if err := validateGeneration(p.Name(), req.GenerationParams); err != nil {
+		return models.AIResponse{Error: PublicAIError(err), ErrorCategory: ClassifyAIError(err)}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	response, err := p.Complete(ctx, req)
+	duration := time.Since(start).Seconds()
+
+	if err != nil {
+		result := models.AIResponse{
+			Error:         PublicAIError(err),
+			ErrorCategory: ClassifyAIError(err),
+			Time:          duration,
+		}
+		if result.ErrorCategory == ErrorCategoryContentBlocked {
+			result.FinishReason = models.FinishReasonContentFilter
+		}
+		return result
+	}
+
+	RegistrarUsoIA(ctx, req, response)
+
+	usage := response.Usage
+	return models.AIResponse{
+		Response:     response.Text,
+		Model:        response.Model,
+		Usage:        &usage,
+		FinishReason: response.FinishReason,
+		Time:         duration,
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"lingobotAPI-GO/utils"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AICache guarda respostas de IA para prompts idênticos
+type AICache interface {
+	Get(ctx context.Context, key string) (*models.CompletionResponse, bool)
+	Set(ctx context.Context, key string, response *models.CompletionResponse)
+}
+
+var (
+	aiCacheOnce sync.Once
+	aiCache     AICache
+)
+
+// getAICache cria o cache configurado em AI_CACHE na primeira chamada (nil = desligado)
+func getAICache() AICache {
+	aiCacheOnce.Do(func() {
+		cfg := config.GetAICacheConfig()
+		switch cfg.Backend {
+		case "off", "none", "false":
+			aiCache = nil
+		case "postgres":
+			aiCache = newPostgresAICache(cfg.TTL, cfg.MaxEntries)
+		case "memory":
+			aiCache = newMemoryAICache(cfg.TTL, cfg.MaxEntries)
+		default:
+			log.Printf("⚠️  Aviso: AI_CACHE %q desconhecido, usando memória", cfg.Backend)
+			aiCache = newMemoryAICache(cfg.TTL, cfg.MaxEntries)
+		}
+	})
+	return aiCache
+}
+
+// CallAIWithCache consulta o cache antes de chamar CallAIWithFallback.
+// Retorna true quando a resposta veio do cache.
+func CallAIWithCache(ctx context.Context, req models.CompletionRequest, requested []string) (*models.CompletionResponse, bool, error) {
+	scope := "fallback:" + strings.ToLower(strings.Join(requested, ","))
+	return cachedCompletion(ctx, req, scope, func() (*models.CompletionResponse, error) {
+		return CallAIWithFallback(ctx, req, requested)
+	})
+}
+
+// CallProviderWithCache consulta o cache antes de chamar CallProvider.
+// Retorna true quando a resposta veio do cache.
+func CallProviderWithCache(ctx context.Context, name string, req models.CompletionRequest) (*models.CompletionResponse, bool, error) {
+	return cachedCompletion(ctx, req, "provider:"+name, func() (*models.CompletionResponse, error) {
+		return CallProvider(ctx, name, req)
+	})
+}
+
+// cachedCompletion só usa o cache para prompts sem histórico (conversas não se repetem)
+func cachedCompletion(ctx context.Context, req models.CompletionRequest, scope string, call func() (*models.CompletionResponse, error)) (*models.CompletionResponse, bool, error) {
+	cache := getAICache()
+	if cache == nil || len(req.History) > 0 {
+		response, err := call()
+		return response, false, err
+	}
+
+	key := aiCacheKey(req, scope)
+	if response, ok := cache.Get(ctx, key); ok {
+		return response, true, nil
+	}
+
+	response, err := call()
+	if err != nil {
+		return nil, false, err
+	}
+
+	cache.Set(ctx, key, response)
+	return response, false, nil
+}
+
+// aiCacheKey gera a chave a partir do prompt normalizado, do system prompt (persona já
+// renderizada com idioma e dificuldade), do schema, do apelido de modelo, dos parâmetros de geração e dos provedores pedidos
+func aiCacheKey(req models.CompletionRequest, scope string) string {
+	schema := ""
+	if req.ResponseSchema != nil {
+		schema, _ = utils.MarshalString(req.ResponseSchema)
+	}
+
+	h := sha256.New()
+	model := config.GetAIConfig().ModelAlias(req.Model)
+	params, _ := utils.MarshalString(req.GenerationParams)
+	for _, part := range []string{scope, model, params, req.System, schema, normalizePrompt(req.Prompt)} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizePrompt ignora caixa e espaços extras ("Explain  present perfect " == "explain present perfect")
+func normalizePrompt(prompt string) string {
+	return strings.Join(strings.Fields(strings.ToLower(prompt)), " ")
+}
+
+// memoryAICache é um cache LRU em memória com TTL
+type memoryAICache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List // mais recente na frente
+	entries    map[string]*list.Element
+}
+
+type memoryAICacheEntry struct {
+	key       string
+	response  models.CompletionResponse
+	expiresAt time.Time
+}
+
+func newMemoryAICache(ttl time.Duration, maxEntries int) *memoryAICache {
+	return &memoryAICache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (c *memoryAICache) Get(_ context.Context, key string) (*models.CompletionResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryAICacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	response := entry.response
+	return &response, true
+}
+
+func (c *memoryAICache) Set(_ context.Context, key string, response *models.CompletionResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryAICacheEntry{key: key, response: *response, expiresAt: time.Now().Add(c.ttl)}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryAICacheEntry).key)
+	}
+}
+
+// postgresAICache guarda as respostas na tabela ai_cache (compartilhado entre instâncias)
+type postgresAICache struct {
+	ttl        time.Duration
+	maxEntries int
+	mu         sync.Mutex
+	lastPrune  time.Time
+}
+
+// postgresAICachePruneEvery define de quanto em quanto tempo a tabela é limpa
+const postgresAICachePruneEvery = 5 * time.Minute
+
+func newPostgresAICache(ttl time.Duration, maxEntries int) *postgresAICache {
+	return &postgresAICache{ttl: ttl, maxEntries: maxEntries}
+}
+
+func (c *postgresAICache) Get(ctx context.Context, key string) (*models.CompletionResponse, bool) {
+	data, err := repositories.GetAICache(ctx, key)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("⚠️  Aviso: erro ao ler ai_cache: %v", err)
+		}
+		return nil, false
+	}
+
+	var response models.CompletionResponse
+	if err := utils.Unmarshal(data, &response); err != nil {
+		return nil, false
+	}
+
+	return &response, true
+}
+
+func (c *postgresAICache) Set(ctx context.Context, key string, response *models.CompletionResponse) {
+	data, err := utils.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	if err := repositories.UpsertAICache(ctx, key, data, c.ttl); err != nil {
+		log.Printf("⚠️  Aviso: %v", err)
+		return
+	}
+
+	c.mu.Lock()
+	prune := time.Since(c.lastPrune) >= postgresAICachePruneEvery
+	if prune {
+		c.lastPrune = time.Now()
+	}
+	c.mu.Unlock()
+
+	if prune {
+		if err := repositories.PruneAICache(ctx, c.maxEntries); err != nil {
+			log.Printf("⚠️  Aviso: %v", err)
+		}
+	}
+}
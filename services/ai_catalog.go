@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"sort"
+)
+
+var (
+	// ErrModelNotFound é retornado quando nenhum provedor tem o apelido de modelo pedido
+	ErrModelNotFound = errors.New("model alias not found")
+	// ErrModelRequiresPremium é retornado quando o apelido é exclusivo dos planos premium
+	ErrModelRequiresPremium = errors.New("model requires a premium plan")
+)
+
+// AutorizarModeloIA confere se o apelido existe e se o plano do usuário pode usá-lo.
+// Um apelido é premium se for premium em qualquer provedor da cadeia. O plano vem de
+// usuario_economia.plano, que só o servidor altera (DefinirPlano, pelo PUT /admin/usuarios/:id/plano).
+func AutorizarModeloIA(usuarioID int, alias string) error {
+	premium, found := modelAliasInfo(alias)
+	if !found {
+		return fmt.Errorf("%w: %s", ErrModelNotFound, alias)
+	}
+	if !premium {
+		return nil
+	}
+
+	economia, err := repositories.GetUsuarioEconomia(usuarioID)
+	if err != nil {
+		return errors.New("erro ao buscar economia do usuário")
+	}
+
+	if !regraDoPlano(economia.Plano).ModelosPremium {
+		return fmt.Errorf("%w: %s", ErrModelRequiresPremium, alias)
+	}
+	return nil
+}
+
+// ModelCatalog lista os apelidos disponíveis e o modelo de cada provedor
+func ModelCatalog() []models.AIModelInfo {
+	cfg := config.GetAIConfig()
+
+	aliases := map[string]*models.AIModelInfo{}
+	for _, p := range ListProviders() {
+		for _, alias := range cfg.ModelAliases(p.Name()) {
+			model, ok := cfg.Model(p.Name(), alias)
+			if !ok {
+				continue
+			}
+			info, exists := aliases[alias]
+			if !exists {
+				info = &models.AIModelInfo{Alias: alias, Default: alias == cfg.ModelAlias(""), Providers: map[string][]string{}}
+				aliases[alias] = info
+			}
+			info.Premium = info.Premium || model.Premium
+			info.Providers[p.Name()] = model.IDs
+		}
+	}
+
+	catalog := make([]models.AIModelInfo, 0, len(aliases))
+	for _, info := range aliases {
+		catalog = append(catalog, *info)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Alias < catalog[j].Alias })
+	return catalog
+}
+
+// modelAliasInfo informa se o apelido existe em algum provedor e se é premium em algum deles
+func modelAliasInfo(alias string) (premium, found bool) {
+	cfg := config.GetAIConfig()
+	for _, p := range ListProviders() {
+		if model, ok := cfg.Model(p.Name(), alias); ok {
+			found = true
+			premium = premium || model.Premium
+		}
+	}
+	return premium, found
+}
+
+// providerModels retorna os IDs de modelo do provedor para o apelido do pedido.
+// Se o provedor não tiver o apelido, vale o modelo padrão dele.
+func providerModels(provider string, req models.CompletionRequest) []string {
+	cfg := config.GetAIConfig()
+	if model, ok := cfg.Model(provider, req.Model); ok {
+		return model.IDs
+	}
+	model, _ := cfg.Model(provider, "")
+	return model.IDs
+}
+
+// providerModel retorna o primeiro modelo do provedor para o apelido do pedido
+func providerModel(provider string, req models.CompletionRequest) string {
+	if ids := providerModels(provider, req); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"net/http"
+)
+
+// cohereProvider chama a API do Cohere
+type cohereProvider struct{}
+
+type cohereChatRequest struct {
+	Message        string                `json:"message"`
+	ChatHistory    []cohereMessage       `json:"chat_history"`
+	Model          string                `json:"model"`
+	Preamble       string                `json:"preamble,omitempty"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	StopSequences  []string              `json:"stop_sequences,omitempty"`
+	ResponseFormat *cohereResponseFormat `json:"response_format,omitempty"`
+}
+
+type cohereMessage struct {
+	Role    string `json:"role"`
+	Message string `json:"message"`
+}
+
+type cohereResponseFormat struct {
+	Type   string                 `json:"type"`
+	Schema map[string]interface{} `json:"schema,omitempty"`
+}
+
+type cohereChatResponse struct {
+	Text         string `json:"text"`
+	FinishReason string `json:"finish_reason"`
+	Meta         struct {
+		BilledUnits struct {
+			// O Cohere devolve as contagens como número de ponto flutuante
+			InputTokens  float64 `json:"input_tokens"`
+			OutputTokens float64 `json:"output_tokens"`
+		} `json:"billed_units"`
+	} `json:"meta"`
+}
+
+// cohereErrorResponse é o corpo de erro do Cohere ({"message": ...})
+type cohereErrorResponse struct {
+	Message string `json:"message"`
+}
+
+func init() {
+	RegisterProvider(cohereProvider{})
+}
+
+func (cohereProvider) Name() string {
+	return "cohere"
+}
+
+func (p cohereProvider) Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "cohere", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.complete(ctx, req, apiKey)
+	})
+}
+
+// complete faz a chamada com uma chave do pool (withAPIKey)
+func (p cohereProvider) complete(ctx context.Context, req models.CompletionRequest, apiKey string) (*models.CompletionResponse, error) {
+	url := providerURL("cohere", "/chat")
+	model := providerModel("cohere", req)
+
+	g := generationFor("cohere", req)
+	payload := cohereChatRequest{
+		Message:       req.Prompt,
+		ChatHistory:   cohereChatHistory(req.History),
+		Model:         model,
+		Preamble:      req.System,
+		Temperature:   g.Temperature,
+		MaxTokens:     g.MaxTokens,
+		StopSequences: g.Stop,
+	}
+	if req.ResponseSchema != nil {
+		payload.ResponseFormat = &cohereResponseFormat{Type: "json_object", Schema: req.ResponseSchema}
+	}
+
+	status, body, err := postJSON(ctx, url, map[string]string{"Authorization": "Bearer " + apiKey}, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &ProviderStatusError{Provider: "cohere", StatusCode: status, Message: cohereErrorMessage(body)}
+	}
+
+	var result cohereChatResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("%w: cohere: %v", ErrEmptyAIResponse, err)
+	}
+
+	finishReason := cohereFinishReason(result.FinishReason)
+	if finishReason == models.FinishReasonContentFilter {
+		return nil, &ContentBlockedError{Provider: "cohere", Reason: result.FinishReason}
+	}
+	if result.Text == "" {
+		return nil, fmt.Errorf("%w: cohere", ErrEmptyAIResponse)
+	}
+
+	usage := models.AIUsage{
+		PromptTokens:     int(result.Meta.BilledUnits.InputTokens),
+		CompletionTokens: int(result.Meta.BilledUnits.OutputTokens),
+	}
+	return &models.CompletionResponse{Text: result.Text, Provider: p.Name(), Model: model, Usage: usage, FinishReason: finishReason}, nil
+}
+
+// cohereChatHistory monta o histórico no formato `chat_history` do Cohere (papéis USER e CHATBOT)
+func cohereChatHistory(history []models.AIMessage) []cohereMessage {
+	chatHistory := make([]cohereMessage, 0, len(history))
+	for _, m := range history {
+		role := "USER"
+		if m.Role == models.RoleAssistant {
+			role = "CHATBOT"
+		}
+		chatHistory = append(chatHistory, cohereMessage{Role: role, Message: m.Content})
+	}
+	return chatHistory
+}
+
+// cohereFinishReason normaliza o finish_reason do Cohere
+func cohereFinishReason(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case "COMPLETE", "STOP_SEQUENCE":
+		return models.FinishReasonStop
+	case "MAX_TOKENS":
+		return models.FinishReasonLength
+	case "ERROR_TOXIC":
+		return models.FinishReasonContentFilter
+	default:
+		return models.FinishReasonOther
+	}
+}
+
+// cohereErrorMessage lê a mensagem do corpo de erro do Cohere
+func cohereErrorMessage(body []byte) string {
+	var result cohereErrorResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+	return result.Message
+}
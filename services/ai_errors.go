@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrEmptyAIResponse é retornado quando o provedor responde 200 mas sem texto
+var ErrEmptyAIResponse = errors.New("no text found in AI response")
+
+// ProviderStatusError é retornado quando o provedor responde com status HTTP diferente de 200
+type ProviderStatusError struct {
+	Provider   string
+	StatusCode int
+	Message    string // mensagem de erro do fornecedor, quando o corpo informa
+}
+
+func (e *ProviderStatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s API returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s API returned status %d", e.Provider, e.StatusCode)
+}
+
+// ContentBlockedError é retornado quando o filtro de segurança do provedor bloqueia o prompt
+// ou a resposta (ex.: finishReason SAFETY no Gemini, content_filter nas APIs OpenAI)
+type ContentBlockedError struct {
+	Provider string
+	Reason   string // motivo informado pelo fornecedor
+}
+
+func (e *ContentBlockedError) Error() string {
+	return fmt.Sprintf("%s blocked the response (%s)", e.Provider, e.Reason)
+}
+
+// ProviderAttempt é a falha de um provedor da cadeia de fallback
+type ProviderAttempt struct {
+	Provider string
+	Err      error
+}
+
+// FallbackError é retornado quando todos os provedores da cadeia de fallback falham
+type FallbackError struct {
+	Attempts []ProviderAttempt
+}
+
+func (e *FallbackError) Error() string {
+	lines := make([]string, 0, len(e.Attempts))
+	for _, a := range e.Attempts {
+		lines = append(lines, fmt.Sprintf("%s: %v", a.Provider, a.Err))
+	}
+	return "all AI services failed: " + strings.Join(lines, "\n")
+}
+
+func (e *FallbackError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts))
+	for _, a := range e.Attempts {
+		errs = append(errs, a.Err)
+	}
+	return errs
+}
+
+// fallbackError devolve o erro original quando só um provedor foi tentado
+func fallbackError(attempts []ProviderAttempt) error {
+	if len(attempts) == 1 {
+		return attempts[0].Err
+	}
+	return &FallbackError{Attempts: attempts}
+}
+
+// Categorias de erro reportadas no benchmark
+const (
+	ErrorCategoryTimeout         = "timeout"
+	ErrorCategoryCanceled        = "canceled"
+	ErrorCategoryRateLimited     = "rate_limited"
+	ErrorCategoryAuth            = "auth"
+	ErrorCategoryUnavailable     = "unavailable"
+	ErrorCategoryInvalidResponse = "invalid_response"
+	ErrorCategoryInvalidRequest  = "invalid_request"
+	ErrorCategoryContentBlocked  = "content_blocked"
+	ErrorCategoryNetwork         = "network"
+	ErrorCategoryUnknown         = "unknown"
+)
+
+// ClassifyAIError agrupa o erro de um provedor em uma categoria estável
+func ClassifyAIError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var statusErr *ProviderStatusError
+	var blockedErr *ContentBlockedError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCategoryCanceled
+	case errors.As(err, &statusErr):
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests:
+			return ErrorCategoryRateLimited
+		case statusErr.StatusCode == http.StatusUnauthorized, statusErr.StatusCode == http.StatusForbidden:
+			return ErrorCategoryAuth
+		case statusErr.StatusCode >= http.StatusInternalServerError:
+			return ErrorCategoryUnavailable
+		default:
+			return ErrorCategoryInvalidResponse
+		}
+	case errors.As(err, &blockedErr):
+		return ErrorCategoryContentBlocked
+	case errors.Is(err, ErrEmptyAIResponse):
+		return ErrorCategoryInvalidResponse
+	case errors.Is(err, ErrInvalidGenerationParams):
+		return ErrorCategoryInvalidRequest
+	case errors.Is(err, ErrAPIKeysExhausted):
+		return ErrorCategoryRateLimited
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, ErrProviderDisabled):
+		return ErrorCategoryUnavailable
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return ErrorCategoryTimeout
+		}
+		return ErrorCategoryNetwork
+	case strings.Contains(err.Error(), "API key not configured"):
+		return ErrorCategoryAuth
+	default:
+		return ErrorCategoryUnknown
+	}
+}
+
+// PublicAIError devolve a mensagem do erro que pode ir para o cliente. Falhas vindas do
+// fornecedor (transporte, status HTTP, corpo da resposta) podem conter URLs e detalhes internos:
+// viram só provedor, status e categoria, e o erro completo fica no log.
+func PublicAIError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var fallback *FallbackError
+	if errors.As(err, &fallback) {
+		parts := make([]string, 0, len(fallback.Attempts))
+		for _, a := range fallback.Attempts {
+			parts = append(parts, a.Provider+": "+publicProviderError(a.Err))
+		}
+		return "all AI services failed: " + strings.Join(parts, "; ")
+	}
+
+	if errors.Is(err, ErrStreamInterrupted) {
+		log.Printf("⚠️ Stream de IA interrompido: %v", err)
+		return fmt.Sprintf("%s (%s)", ErrStreamInterrupted, ClassifyAIError(err))
+	}
+
+	return publicProviderError(err)
+}
+
+func publicProviderError(err error) string {
+	category := ClassifyAIError(err)
+	switch category {
+	case ErrorCategoryTimeout, ErrorCategoryNetwork, ErrorCategoryRateLimited, ErrorCategoryAuth,
+		ErrorCategoryUnavailable, ErrorCategoryInvalidResponse:
+	default:
+		return err.Error()
+	}
+
+	// Erros gerados aqui mesmo não trazem dados do fornecedor
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrProviderDisabled) || errors.Is(err, ErrAPIKeysExhausted) {
+		return err.Error()
+	}
+
+	log.Printf("⚠️ Erro do provedor de IA (%s): %v", category, err)
+
+	var statusErr *ProviderStatusError
+	if errors.As(err, &statusErr) {
+		return fmt.Sprintf("%s API returned status %d (%s)", statusErr.Provider, statusErr.StatusCode, category)
+	}
+	return fmt.Sprintf("AI provider request failed (%s)", category)
+}
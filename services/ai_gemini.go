@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strings"
+)
+
+// geminiProvider chama a API do Google Gemini
+type geminiProvider struct{}
+
+type geminiRequest struct {
+	Contents          []geminiContent        `json:"contents"`
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature        float64                `json:"temperature"`
+	MaxOutputTokens    int                    `json:"maxOutputTokens,omitempty"`
+	StopSequences      []string               `json:"stopSequences,omitempty"`
+	ResponseMimeType   string                 `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema map[string]interface{} `json:"responseJsonSchema,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates     []geminiCandidate     `json:"candidates"`
+	PromptFeedback *geminiPromptFeedback `json:"promptFeedback"`
+	UsageMetadata  *geminiUsageMetadata  `json:"usageMetadata"`
+	ModelVersion   string                `json:"modelVersion"`
+}
+
+type geminiCandidate struct {
+	Content      geminiContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+type geminiPromptFeedback struct {
+	BlockReason string `json:"blockReason"`
+}
+
+type geminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+}
+
+// geminiErrorResponse é o corpo de erro do Google ({"error": {"code", "message", "status"}})
+type geminiErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+func init() {
+	RegisterProvider(geminiProvider{})
+}
+
+func (geminiProvider) Name() string {
+	return "gemini"
+}
+
+func (p geminiProvider) Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "gemini", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.complete(ctx, req, apiKey)
+	})
+}
+
+// complete faz a chamada com uma chave do pool (withAPIKey)
+func (p geminiProvider) complete(ctx context.Context, req models.CompletionRequest, apiKey string) (*models.CompletionResponse, error) {
+	model := providerModel("gemini", req)
+	url := providerURL("gemini", fmt.Sprintf("/models/%s:generateContent", model))
+
+	status, body, err := postJSON(ctx, url, geminiHeaders(apiKey), geminiPayload(req))
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &ProviderStatusError{Provider: "gemini", StatusCode: status, Message: geminiErrorMessage(body)}
+	}
+
+	var result geminiResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("%w: gemini: %v", ErrEmptyAIResponse, err)
+	}
+
+	text, finishReason, err := result.text()
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, fmt.Errorf("%w: gemini", ErrEmptyAIResponse)
+	}
+
+	return &models.CompletionResponse{
+		Text:         text,
+		Provider:     p.Name(),
+		Model:        model,
+		Usage:        result.UsageMetadata.toAIUsage(),
+		FinishReason: finishReason,
+	}, nil
+}
+
+// Stream usa o streamGenerateContent do Gemini (alt=sse)
+func (p geminiProvider) Stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "gemini", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.stream(ctx, req, onToken, apiKey)
+	})
+}
+
+// stream faz o stream com uma chave do pool (withAPIKey)
+func (p geminiProvider) stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error, apiKey string) (*models.CompletionResponse, error) {
+	model := providerModel("gemini", req)
+	url := providerURL("gemini", fmt.Sprintf("/models/%s:streamGenerateContent?alt=sse", model))
+
+	body, err := postStream(ctx, "gemini", url, geminiHeaders(apiKey), geminiPayload(req), geminiErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var sb strings.Builder
+	var usage models.AIUsage
+	var finishReason string
+	err = readSSE(body, func(data []byte) error {
+		var chunk geminiResponse
+		if err := utils.Unmarshal(data, &chunk); err != nil {
+			return err
+		}
+
+		// Cada chunk traz o uso acumulado; fica valendo o último
+		if chunk.UsageMetadata != nil {
+			usage = chunk.UsageMetadata.toAIUsage()
+		}
+
+		text, reason, err := chunk.text()
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			finishReason = reason
+		}
+		if text != "" {
+			sb.WriteString(text)
+			return onToken(text)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sb.Len() == 0 {
+		return nil, fmt.Errorf("%w: gemini", ErrEmptyAIResponse)
+	}
+
+	return &models.CompletionResponse{Text: sb.String(), Provider: p.Name(), Model: model, Usage: usage, FinishReason: finishReason}, nil
+}
+
+// geminiHeaders envia a chave no header (na query string ela vazaria nas mensagens de erro do *url.Error)
+func geminiHeaders(apiKey string) map[string]string {
+	return map[string]string{"x-goog-api-key": apiKey}
+}
+
+// text junta as partes do primeiro candidato e normaliza o finishReason.
+// Prompt bloqueado ou candidato barrado pelos filtros viram ContentBlockedError.
+func (r geminiResponse) text() (string, string, error) {
+	if r.PromptFeedback != nil && r.PromptFeedback.BlockReason != "" {
+		return "", "", &ContentBlockedError{Provider: "gemini", Reason: r.PromptFeedback.BlockReason}
+	}
+	if len(r.Candidates) == 0 {
+		return "", "", nil
+	}
+
+	candidate := r.Candidates[0]
+	finishReason := geminiFinishReason(candidate.FinishReason)
+	if finishReason == models.FinishReasonContentFilter {
+		return "", "", &ContentBlockedError{Provider: "gemini", Reason: candidate.FinishReason}
+	}
+
+	var sb strings.Builder
+	for _, part := range candidate.Content.Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String(), finishReason, nil
+}
+
+func (u *geminiUsageMetadata) toAIUsage() models.AIUsage {
+	if u == nil {
+		return models.AIUsage{}
+	}
+	return models.AIUsage{PromptTokens: u.PromptTokenCount, CompletionTokens: u.CandidatesTokenCount}
+}
+
+// geminiFinishReason normaliza o finishReason do Gemini
+func geminiFinishReason(reason string) string {
+	switch reason {
+	case "", "FINISH_REASON_UNSPECIFIED":
+		return ""
+	case "STOP":
+		return models.FinishReasonStop
+	case "MAX_TOKENS":
+		return models.FinishReasonLength
+	case "SAFETY", "RECITATION", "BLOCKLIST", "PROHIBITED_CONTENT", "SPII", "IMAGE_SAFETY":
+		return models.FinishReasonContentFilter
+	default:
+		return models.FinishReasonOther
+	}
+}
+
+// geminiErrorMessage lê a mensagem do corpo de erro do Gemini
+func geminiErrorMessage(body []byte) string {
+	var result geminiErrorResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+	return result.Error.Message
+}
+
+// geminiPayload monta o corpo da requisição do Gemini (generateContent e streamGenerateContent)
+func geminiPayload(req models.CompletionRequest) geminiRequest {
+	g := generationFor("gemini", req)
+
+	payload := geminiRequest{
+		Contents: geminiContents(req),
+		GenerationConfig: geminiGenerationConfig{
+			Temperature:     g.Temperature,
+			MaxOutputTokens: g.MaxTokens,
+			StopSequences:   g.Stop,
+		},
+	}
+	if req.System != "" {
+		payload.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: req.System}}}
+	}
+	if req.ResponseSchema != nil {
+		payload.GenerationConfig.ResponseMimeType = "application/json"
+		payload.GenerationConfig.ResponseJSONSchema = req.ResponseSchema
+	}
+	return payload
+}
+
+// geminiContents monta o histórico no formato `contents` do Gemini (papéis "user" e "model")
+func geminiContents(req models.CompletionRequest) []geminiContent {
+	contents := make([]geminiContent, 0, len(req.History)+1)
+	for _, m := range req.History {
+		role := "user"
+		if m.Role == models.RoleAssistant {
+			role = "model"
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}})
+	}
+	return append(contents, geminiContent{Role: "user", Parts: []geminiPart{{Text: req.Prompt}}})
+}
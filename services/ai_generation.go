@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+)
+
+// ErrInvalidGenerationParams é retornado quando temperature, max_tokens ou stop passam dos
+// limites do provedor
+var ErrInvalidGenerationParams = errors.New("invalid generation parameters")
+
+// defaultTemperature vale quando a requisição não informa temperature
+const defaultTemperature = 0.7
+
+// generationLimits são os limites aceitos pela API de cada provedor
+type generationLimits struct {
+	maxTemperature   float64
+	maxTokens        int
+	maxStop          int
+	defaultMaxTokens int // 0 = não envia (vale o padrão do fornecedor)
+}
+
+var providerGenerationLimits = map[string]generationLimits{
+	"gemini":     {maxTemperature: 2, maxTokens: 8192, maxStop: 5},
+	"mistral":    {maxTemperature: 1.5, maxTokens: 4096, maxStop: 4, defaultMaxTokens: 2000},
+	"cohere":     {maxTemperature: 1, maxTokens: 4000, maxStop: 5, defaultMaxTokens: 1000},
+	"groq":       {maxTemperature: 2, maxTokens: 8192, maxStop: 4},
+	"openrouter": {maxTemperature: 2, maxTokens: 4096, maxStop: 4, defaultMaxTokens: 1000},
+}
+
+// fallbackGenerationLimits vale para provedores sem limites cadastrados
+var fallbackGenerationLimits = generationLimits{maxTemperature: 1, maxTokens: 4096, maxStop: 4}
+
+// generation são os parâmetros já resolvidos (com os padrões) para montar o payload
+type generation struct {
+	Temperature float64
+	MaxTokens   int // 0 = não envia
+	Stop        []string
+}
+
+func limitsFor(provider string) generationLimits {
+	if limits, ok := providerGenerationLimits[provider]; ok {
+		return limits
+	}
+	return fallbackGenerationLimits
+}
+
+// validateGeneration confere os parâmetros da requisição contra os limites do provedor
+func validateGeneration(provider string, params models.GenerationParams) error {
+	limits := limitsFor(provider)
+
+	if t := params.Temperature; t != nil && (*t < 0 || *t > limits.maxTemperature) {
+		return fmt.Errorf("%w: %s: temperature deve estar entre 0 e %v", ErrInvalidGenerationParams, provider, limits.maxTemperature)
+	}
+	if m := params.MaxTokens; m != nil && (*m <= 0 || *m > limits.maxTokens) {
+		return fmt.Errorf("%w: %s: max_tokens deve estar entre 1 e %d", ErrInvalidGenerationParams, provider, limits.maxTokens)
+	}
+	if len(params.Stop) > limits.maxStop {
+		return fmt.Errorf("%w: %s: no máximo %d sequências em stop", ErrInvalidGenerationParams, provider, limits.maxStop)
+	}
+	for _, stop := range params.Stop {
+		if stop == "" {
+			return fmt.Errorf("%w: %s: stop não aceita sequência vazia", ErrInvalidGenerationParams, provider)
+		}
+	}
+
+	return nil
+}
+
+// generationFor resolve os parâmetros do pedido com os padrões do provedor
+func generationFor(provider string, req models.CompletionRequest) generation {
+	g := generation{
+		Temperature: defaultTemperature,
+		MaxTokens:   limitsFor(provider).defaultMaxTokens,
+		Stop:        req.Stop,
+	}
+	if req.Temperature != nil {
+		g.Temperature = *req.Temperature
+	}
+	if req.MaxTokens != nil {
+		g.MaxTokens = *req.MaxTokens
+	}
+	return g
+}
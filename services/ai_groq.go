@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"lingobotAPI-GO/models"
+	"net/http"
+)
+
+// groqProvider chama a API do Groq (formato OpenAI)
+type groqProvider struct{}
+
+func init() {
+	RegisterProvider(groqProvider{})
+}
+
+func (groqProvider) Name() string {
+	return "groq"
+}
+
+func (p groqProvider) Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "groq", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.complete(ctx, req, apiKey)
+	})
+}
+
+// complete faz a chamada com uma chave do pool (withAPIKey)
+func (p groqProvider) complete(ctx context.Context, req models.CompletionRequest, apiKey string) (*models.CompletionResponse, error) {
+	url := providerURL("groq", "/chat/completions")
+	model := providerModel("groq", req)
+
+	status, body, err := postJSON(ctx, url, map[string]string{"Authorization": "Bearer " + apiKey}, openAIChatPayload("groq", req, model))
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &ProviderStatusError{Provider: "groq", StatusCode: status, Message: openAIErrorMessage(body)}
+	}
+
+	return openAICompletion(p.Name(), model, body)
+}
+
+// Stream usa o modo `stream: true` da API do Groq
+func (p groqProvider) Stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "groq", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.stream(ctx, req, onToken, apiKey)
+	})
+}
+
+// stream faz o stream com uma chave do pool (withAPIKey)
+func (p groqProvider) stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error, apiKey string) (*models.CompletionResponse, error) {
+	model := providerModel("groq", req)
+	payload := openAIChatPayload("groq", req, model)
+	payload.Stream = true
+	payload.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+
+	body, err := postStream(ctx, "groq", providerURL("groq", "/chat/completions"), map[string]string{"Authorization": "Bearer " + apiKey}, payload, openAIErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return openAIStreamCompletion(p.Name(), model, body, onToken)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	keyBenchBase = time.Minute // primeira suspensão de uma chave após erro de cota
+	keyBenchMax  = time.Hour   // limite da suspensão (dobra a cada erro de cota seguido)
+)
+
+// keyEnvPrefixes são os prefixos das variáveis de ambiente com as chaves de cada fornecedor.
+// Vale a variável sem número e as numeradas: GOOGLE_GEMINI_API_KEY, GOOGLE_GEMINI_API_KEY1, ...
+var keyEnvPrefixes = map[string]string{
+	"gemini":     "GOOGLE_GEMINI_API_KEY",
+	"mistral":    "MISTRAL_KEY",
+	"cohere":     "COHERE_KEY",
+	"groq":       "GROQ_KEY",
+	"openrouter": "OPENROUTER_KEY",
+	"elevenlabs": "ELEVENLABS_KEY",
+	"assemblyai": "ASSEMBLYAI_KEY",
+}
+
+// ErrAPIKeysExhausted é retornado quando todas as chaves do fornecedor estão suspensas
+var ErrAPIKeysExhausted = errors.New("all API keys are rate limited")
+
+// apiKeyState acompanha o uso de uma chave
+type apiKeyState struct {
+	name             string // variável de ambiente de origem (a chave nunca é exposta)
+	value            string
+	requests         int
+	successes        int
+	failures         int
+	quotaErrors      int
+	consecutiveQuota int
+	benchedUntil     time.Time
+	lastUsed         time.Time
+	lastError        string
+	lastErrorAt      time.Time
+}
+
+// keyPool distribui as requisições de um fornecedor entre as chaves em round-robin
+type keyPool struct {
+	mu       sync.Mutex
+	provider string
+	keys     []*apiKeyState
+	next     int
+}
+
+// apiKey é uma chave em uso; Release deve ser chamado com o resultado da chamada
+type apiKey struct {
+	Value string
+	pool  *keyPool
+	state *apiKeyState
+}
+
+var (
+	keyPoolsMu sync.Mutex
+	keyPools   = map[string]*keyPool{}
+)
+
+// keyPoolFor retorna (criando se preciso) o pool de chaves do fornecedor
+func keyPoolFor(provider string) *keyPool {
+	keyPoolsMu.Lock()
+	defer keyPoolsMu.Unlock()
+
+	pool, ok := keyPools[provider]
+	if !ok {
+		pool = &keyPool{provider: provider, keys: loadAPIKeys(keyEnvPrefixes[provider])}
+		keyPools[provider] = pool
+	}
+	return pool
+}
+
+// loadAPIKeys lê a chave sem número e as numeradas (em ordem numérica) do ambiente.
+// Chaves repetidas entram uma vez só.
+func loadAPIKeys(prefix string) []*apiKeyState {
+	if prefix == "" {
+		return nil
+	}
+
+	type numbered struct {
+		n     int
+		state *apiKeyState
+	}
+	var found []numbered
+	seen := map[string]bool{}
+
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		value = strings.TrimSpace(value)
+		if !strings.HasPrefix(name, prefix) || value == "" || seen[value] {
+			continue
+		}
+
+		n := 0
+		if suffix := strings.TrimPrefix(name, prefix); suffix != "" {
+			var err error
+			if n, err = strconv.Atoi(suffix); err != nil {
+				continue // outra variável com o mesmo prefixo
+			}
+		}
+
+		seen[value] = true
+		found = append(found, numbered{n: n, state: &apiKeyState{name: name, value: value}})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].n < found[j].n })
+
+	keys := make([]*apiKeyState, 0, len(found))
+	for _, f := range found {
+		keys = append(keys, f.state)
+	}
+	return keys
+}
+
+// acquireKey escolhe a próxima chave disponível do fornecedor (round-robin, pulando as suspensas)
+func acquireKey(provider string) (*apiKey, error) {
+	pool := keyPoolFor(provider)
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if len(pool.keys) == 0 {
+		// O servidor falso aceita qualquer chave, então dá para rodar sem as chaves reais
+		if config.GetAIConfig().MockURL != "" {
+			return &apiKey{Value: "mock-key"}, nil
+		}
+		return nil, fmt.Errorf("%s API key not configured", provider)
+	}
+
+	now := time.Now()
+	for i := 0; i < len(pool.keys); i++ {
+		state := pool.keys[(pool.next+i)%len(pool.keys)]
+		if now.Before(state.benchedUntil) {
+			continue
+		}
+
+		pool.next = (pool.next + i + 1) % len(pool.keys)
+		state.requests++
+		state.lastUsed = now
+		return &apiKey{Value: state.value, pool: pool, state: state}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrAPIKeysExhausted, provider)
+}
+
+type keyRotationKey struct{}
+
+// withAPIKey faz a chamada com uma chave do pool do fornecedor e registra o resultado nela.
+// Com mais de uma chave no pool, um erro de cota (429/402) suspende a chave e a chamada é
+// refeita com a próxima disponível; nesse caso o doWithRetry não repete o 429 na mesma chave.
+// Esgotadas as chaves, devolve o último erro de cota.
+func withAPIKey[T any](ctx context.Context, provider string, call func(ctx context.Context, apiKey string) (T, error)) (T, error) {
+	var zero T
+	var quotaErr error
+
+	for {
+		key, err := acquireKey(provider)
+		if err != nil {
+			if quotaErr != nil && errors.Is(err, ErrAPIKeysExhausted) {
+				return zero, quotaErr
+			}
+			return zero, err
+		}
+
+		callCtx := ctx
+		rotate := key.pool != nil && key.pool.size() > 1
+		if rotate {
+			callCtx = context.WithValue(ctx, keyRotationKey{}, true)
+		}
+
+		result, err := call(callCtx, key.Value)
+		key.Release(err)
+		if err == nil || !rotate || !isQuotaError(err) {
+			return result, err
+		}
+
+		quotaErr = err
+		log.Printf("⚠️  %s: chave %s sem cota, tentando a próxima", provider, key.state.name)
+	}
+}
+
+// keyRotation informa se a chamada está em um withAPIKey que troca de chave nos erros de cota
+func keyRotation(ctx context.Context) bool {
+	rotate, _ := ctx.Value(keyRotationKey{}).(bool)
+	return rotate
+}
+
+// isQuotaError informa se o fornecedor recusou a chave por cota (429) ou créditos (402)
+func isQuotaError(err error) bool {
+	var statusErr *ProviderStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode == http.StatusPaymentRequired
+}
+
+// size retorna quantas chaves o pool tem
+func (p *keyPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// Release registra o resultado da chamada feita com a chave.
+// Erros de cota (429) suspendem a chave por keyBenchBase, dobrando a cada erro seguido;
+// chaves recusadas (401/403) ficam suspensas por keyBenchMax.
+func (k *apiKey) Release(err error) {
+	if k == nil || k.pool == nil {
+		return
+	}
+
+	k.pool.mu.Lock()
+	defer k.pool.mu.Unlock()
+
+	state := k.state
+	if err == nil {
+		state.successes++
+		state.consecutiveQuota = 0
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		// O cliente desistiu: a chamada não diz nada sobre a chave
+		return
+	}
+
+	var statusErr *ProviderStatusError
+	if !errors.As(err, &statusErr) {
+		// Timeout, rede, resposta inválida: conta como falha, mas não suspende a chave
+		state.failures++
+		return
+	}
+
+	now := time.Now()
+	state.failures++
+	state.lastError = err.Error()
+	state.lastErrorAt = now
+
+	switch statusErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusPaymentRequired:
+		state.quotaErrors++
+		state.consecutiveQuota++
+		bench := keyBenchBase << (state.consecutiveQuota - 1)
+		if bench <= 0 || bench > keyBenchMax {
+			bench = keyBenchMax
+		}
+		state.benchedUntil = now.Add(bench)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		state.benchedUntil = now.Add(keyBenchMax)
+	}
+}
+
+// KeyPoolStatus retorna o estado dos pools de chaves de todos os fornecedores
+func KeyPoolStatus() []models.APIKeyPoolStatus {
+	names := make([]string, 0, len(keyEnvPrefixes))
+	for name := range keyEnvPrefixes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	status := make([]models.APIKeyPoolStatus, 0, len(names))
+	for _, name := range names {
+		pool := keyPoolFor(name)
+
+		pool.mu.Lock()
+		ps := models.APIKeyPoolStatus{Provider: name, Keys: make([]models.APIKeyStatus, 0, len(pool.keys))}
+		for _, state := range pool.keys {
+			ks := models.APIKeyStatus{
+				Name:        state.name,
+				Key:         maskKey(state.value),
+				Requests:    state.requests,
+				Successes:   state.successes,
+				Failures:    state.failures,
+				QuotaErrors: state.quotaErrors,
+				Benched:     now.Before(state.benchedUntil),
+				LastError:   state.lastError,
+			}
+			if ks.Benched {
+				benchedUntil := state.benchedUntil
+				ks.BenchedUntil = &benchedUntil
+			} else {
+				ps.Available++
+			}
+			if !state.lastUsed.IsZero() {
+				lastUsed := state.lastUsed
+				ks.LastUsed = &lastUsed
+			}
+			if !state.lastErrorAt.IsZero() {
+				lastErrorAt := state.lastErrorAt
+				ks.LastErrorAt = &lastErrorAt
+			}
+			ps.Keys = append(ps.Keys, ks)
+		}
+		pool.mu.Unlock()
+
+		status = append(status, ps)
+	}
+
+	return status
+}
+
+// maskKey mostra só os 4 últimos caracteres da chave
+func maskKey(value string) string {
+	if len(value) <= 4 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
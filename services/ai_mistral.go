@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"net/http"
+)
+
+// mistralProvider chama a API do Mistral
+type mistralProvider struct{}
+
+// mistralErrorResponse é o corpo de erro da Mistral ({"object": "error", "message": ...})
+type mistralErrorResponse struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+func init() {
+	RegisterProvider(mistralProvider{})
+}
+
+func (mistralProvider) Name() string {
+	return "mistral"
+}
+
+func (p mistralProvider) Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "mistral", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.complete(ctx, req, apiKey)
+	})
+}
+
+// complete faz a chamada com uma chave do pool (withAPIKey)
+func (p mistralProvider) complete(ctx context.Context, req models.CompletionRequest, apiKey string) (*models.CompletionResponse, error) {
+	url := providerURL("mistral", "/chat/completions")
+	model := providerModel("mistral", req)
+
+	status, body, err := postJSON(ctx, url, map[string]string{"Authorization": "Bearer " + apiKey}, openAIChatPayload("mistral", req, model))
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		return nil, &ProviderStatusError{Provider: "mistral", StatusCode: status, Message: mistralErrorMessage(body)}
+	}
+
+	return openAICompletion(p.Name(), model, body)
+}
+
+// Stream usa o modo `stream: true` da API do Mistral
+func (p mistralProvider) Stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "mistral", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.stream(ctx, req, onToken, apiKey)
+	})
+}
+
+// stream faz o stream com uma chave do pool (withAPIKey)
+func (p mistralProvider) stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error, apiKey string) (*models.CompletionResponse, error) {
+	model := providerModel("mistral", req)
+	payload := openAIChatPayload("mistral", req, model)
+	payload.Stream = true
+
+	body, err := postStream(ctx, "mistral", providerURL("mistral", "/chat/completions"), map[string]string{"Authorization": "Bearer " + apiKey}, payload, mistralErrorMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return openAIStreamCompletion(p.Name(), model, body, onToken)
+}
+
+// mistralErrorMessage lê a mensagem do corpo de erro da Mistral
+func mistralErrorMessage(body []byte) string {
+	var result mistralErrorResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+	return result.Message
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"lingobotAPI-GO/mockai"
+	"lingobotAPI-GO/models"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limite das esperas de retry nos testes (o Retry-After do roteiro é cortado nele)
+const testRetryMaxDelay = 50 * time.Millisecond
+
+var mockServer *mockai.Server
+
+// TestMain sobe o servidor falso e aponta os fornecedores para ele antes da primeira
+// leitura de config.GetAIConfig (que fica em cache), assim como a chave JWT (carregada uma vez)
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	mockServer = mockai.New(nil)
+	server := httptest.NewServer(mockServer.Handler())
+
+	os.Setenv("AI_MOCK_URL", server.URL)
+	os.Setenv("GOOGLE_GEMINI_API_KEY", "test-gemini-key")
+	os.Setenv("MISTRAL_KEY", "test-mistral-key")
+	os.Setenv("AI_RETRY_MAX_ATTEMPTS", "3")
+	os.Setenv("AI_RETRY_BASE_DELAY", "1ms")
+	os.Setenv("AI_RETRY_MAX_DELAY", testRetryMaxDelay.String())
+	os.Setenv("JWT_SECRET_KEY", "test-jwt-secret") // chave dos tokens nos testes de auth
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+// resetMockAI troca o roteiro e zera os circuit breakers e as chaves suspensas
+func resetMockAI(t *testing.T, script mockai.Script) {
+	t.Helper()
+
+	mockServer.SetScript(script)
+
+	breakersMu.Lock()
+	breakers = map[string]*circuitBreaker{}
+	breakersMu.Unlock()
+
+	keyPoolsMu.Lock()
+	keyPools = map[string]*keyPool{}
+	keyPoolsMu.Unlock()
+}
+
+func TestCallAIWithFallbackRetryAfter(t *testing.T) {
+	resetMockAI(t, mockai.Script{
+		"gemini": {
+			{Status: 429, Error: "Resource has been exhausted (e.g. check quota).", RetryAfter: "1"},
+			{Status: 503, Error: "The model is overloaded."},
+			{Text: "Hello!"},
+		},
+	})
+
+	start := time.Now()
+	response, err := CallAIWithFallback(context.Background(), models.CompletionRequest{Prompt: "hi"}, []string{"gemini"})
+	if err != nil {
+		t.Fatalf("CallAIWithFallback() error = %v", err)
+	}
+
+	if response.Text != "Hello!" || response.Provider != "gemini" {
+		t.Errorf("response = %q de %q, want %q de %q", response.Text, response.Provider, "Hello!", "gemini")
+	}
+	if calls := mockServer.Calls()["gemini"]; calls != 3 {
+		t.Errorf("chamadas ao gemini = %d, want 3", calls)
+	}
+	// O Retry-After (1s, cortado em MaxDelay) vale no lugar do backoff de 1ms
+	if elapsed := time.Since(start); elapsed < testRetryMaxDelay {
+		t.Errorf("retry após %v, want pelo menos %v", elapsed, testRetryMaxDelay)
+	}
+}
+
+func TestCallAIWithFallbackTrocaDeChaveNaCota(t *testing.T) {
+	t.Setenv("GOOGLE_GEMINI_API_KEY2", "test-gemini-key-2")
+	resetMockAI(t, mockai.Script{
+		"gemini": {
+			{Status: 429, Error: "Resource has been exhausted (e.g. check quota).", RetryAfter: "1"},
+			{Text: "Hello!"},
+		},
+	})
+
+	start := time.Now()
+	response, err := CallAIWithFallback(context.Background(), models.CompletionRequest{Prompt: "hi"}, []string{"gemini"})
+	if err != nil {
+		t.Fatalf("CallAIWithFallback() error = %v", err)
+	}
+
+	if response.Text != "Hello!" {
+		t.Errorf("response = %q, want %q", response.Text, "Hello!")
+	}
+	if calls := mockServer.Calls()["gemini"]; calls != 2 {
+		t.Errorf("chamadas ao gemini = %d, want 2", calls)
+	}
+	// Com outra chave no pool, o 429 não espera o Retry-After na mesma chave
+	if elapsed := time.Since(start); elapsed >= testRetryMaxDelay {
+		t.Errorf("troca de chave levou %v, want menos de %v", elapsed, testRetryMaxDelay)
+	}
+
+	for _, pool := range KeyPoolStatus() {
+		if pool.Provider != "gemini" {
+			continue
+		}
+		if len(pool.Keys) != 2 {
+			t.Fatalf("chaves do gemini = %d, want 2", len(pool.Keys))
+		}
+		if first, second := pool.Keys[0], pool.Keys[1]; !first.Benched || first.QuotaErrors != 1 || second.Successes != 1 {
+			t.Errorf("chaves = %+v, want a primeira suspensa e a segunda com o sucesso", pool.Keys)
+		}
+	}
+}
+
+func TestCallAIWithFallbackProximoProvedor(t *testing.T) {
+	resetMockAI(t, mockai.Script{
+		"gemini":  {{Status: 503, Error: "The model is overloaded."}},
+		"mistral": {{Text: "Sure, let's practice."}},
+	})
+
+	response, err := CallAIWithFallback(context.Background(), models.CompletionRequest{Prompt: "hi"}, []string{"gemini", "mistral"})
+	if err != nil {
+		t.Fatalf("CallAIWithFallback() error = %v", err)
+	}
+
+	if response.Provider != "mistral" || response.Text != "Sure, let's practice." {
+		t.Errorf("response = %q de %q, want a resposta do mistral", response.Text, response.Provider)
+	}
+
+	calls := mockServer.Calls()
+	if calls["gemini"] != 3 {
+		t.Errorf("chamadas ao gemini = %d, want 3 (todas as tentativas)", calls["gemini"])
+	}
+	if calls["mistral"] != 1 {
+		t.Errorf("chamadas ao mistral = %d, want 1", calls["mistral"])
+	}
+}
+
+func TestCallProviderAbreCircuito(t *testing.T) {
+	resetMockAI(t, mockai.Script{
+		"gemini": {{Status: 500, Error: "Internal error encountered."}},
+	})
+
+	for i := 0; i < breakerFailureThreshold; i++ {
+		_, err := CallProvider(context.Background(), "gemini", models.CompletionRequest{Prompt: "hi"})
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("chamada %d: error = %v, want erro do fornecedor", i+1, err)
+		}
+		if category := ClassifyAIError(err); category != ErrorCategoryUnavailable {
+			t.Errorf("chamada %d: categoria = %q, want %q", i+1, category, ErrorCategoryUnavailable)
+		}
+	}
+
+	before := mockServer.Calls()["gemini"]
+	if _, err := CallProvider(context.Background(), "gemini", models.CompletionRequest{Prompt: "hi"}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("com o circuito aberto: error = %v, want %v", err, ErrCircuitOpen)
+	}
+	if after := mockServer.Calls()["gemini"]; after != before {
+		t.Errorf("circuito aberto ainda chamou o fornecedor (%d -> %d)", before, after)
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"strings"
+)
+
+// Formato das APIs compatíveis com OpenAI (Mistral, Groq e OpenRouter)
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Stop           []string              `json:"stop,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIChatResponse struct {
+	Model   string         `json:"model"`
+	Choices []openAIChoice `json:"choices"`
+	Usage   *openAIUsage   `json:"usage"`
+	Error   *openAIError   `json:"error"` // OpenRouter pode devolver erro com status 200
+}
+
+type openAIChoice struct {
+	Message      openAIMessage `json:"message"`
+	Delta        openAIMessage `json:"delta"` // chunks do streaming
+	FinishReason string        `json:"finish_reason"`
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+type openAIErrorResponse struct {
+	Error openAIError `json:"error"`
+}
+
+// openAIChatPayload monta o corpo do /chat/completions com os parâmetros de geração do provedor.
+// No JSON mode a API garante JSON válido; o schema vai nas instruções e é validado depois.
+func openAIChatPayload(provider string, req models.CompletionRequest, model string) openAIChatRequest {
+	g := generationFor(provider, req)
+
+	payload := openAIChatRequest{
+		Model:       model,
+		Messages:    openAIMessages(req),
+		Temperature: g.Temperature,
+		MaxTokens:   g.MaxTokens,
+		Stop:        g.Stop,
+	}
+	if req.ResponseSchema != nil {
+		payload.ResponseFormat = &openAIResponseFormat{Type: "json_object"}
+	}
+	return payload
+}
+
+// openAIMessages monta system + histórico + prompt no formato `messages` das APIs compatíveis com OpenAI
+func openAIMessages(req models.CompletionRequest) []openAIMessage {
+	messages := make([]openAIMessage, 0, len(req.History)+2)
+	if req.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, m := range req.History {
+		messages = append(messages, openAIMessage{Role: m.Role, Content: m.Content})
+	}
+	return append(messages, openAIMessage{Role: models.RoleUser, Content: req.Prompt})
+}
+
+// openAICompletion converte a resposta do /chat/completions na resposta comum.
+// O filtro de conteúdo vira ContentBlockedError e resposta sem texto vira ErrEmptyAIResponse.
+func openAICompletion(provider, model string, body []byte) (*models.CompletionResponse, error) {
+	var result openAIChatResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrEmptyAIResponse, provider, err)
+	}
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("%s: %s", provider, result.Error.Message)
+	}
+
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyAIResponse, provider)
+	}
+
+	choice := result.Choices[0]
+	finishReason := openAIFinishReason(choice.FinishReason)
+	if finishReason == models.FinishReasonContentFilter {
+		return nil, &ContentBlockedError{Provider: provider, Reason: choice.FinishReason}
+	}
+	if choice.Message.Content == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyAIResponse, provider)
+	}
+
+	return &models.CompletionResponse{
+		Text:         choice.Message.Content,
+		Provider:     provider,
+		Model:        model,
+		Usage:        result.Usage.toAIUsage(),
+		FinishReason: finishReason,
+	}, nil
+}
+
+// streamOpenAIChat lê um stream no formato OpenAI (choices[0].delta.content) e devolve o texto
+// completo, o uso de tokens (enviado no último chunk, quando o provedor informa) e o finish_reason
+func streamOpenAIChat(provider string, body io.Reader, onToken func(string) error) (string, models.AIUsage, string, error) {
+	var sb strings.Builder
+	var usage models.AIUsage
+	var finishReason string
+
+	err := readSSE(body, func(data []byte) error {
+		var chunk openAIChatResponse
+		if err := utils.Unmarshal(data, &chunk); err != nil {
+			return err
+		}
+
+		if chunk.Error != nil {
+			return fmt.Errorf("%s: %s", provider, chunk.Error.Message)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.toAIUsage()
+		}
+		if len(chunk.Choices) == 0 {
+			return nil
+		}
+
+		choice := chunk.Choices[0]
+		if choice.FinishReason != "" {
+			finishReason = openAIFinishReason(choice.FinishReason)
+			if finishReason == models.FinishReasonContentFilter {
+				return &ContentBlockedError{Provider: provider, Reason: choice.FinishReason}
+			}
+		}
+		if choice.Delta.Content != "" {
+			sb.WriteString(choice.Delta.Content)
+			return onToken(choice.Delta.Content)
+		}
+		return nil
+	})
+
+	return sb.String(), usage, finishReason, err
+}
+
+// openAIStreamCompletion monta a resposta comum de um stream no formato OpenAI
+func openAIStreamCompletion(provider, model string, body io.Reader, onToken func(string) error) (*models.CompletionResponse, error) {
+	text, usage, finishReason, err := streamOpenAIChat(provider, body, onToken)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyAIResponse, provider)
+	}
+
+	return &models.CompletionResponse{Text: text, Provider: provider, Model: model, Usage: usage, FinishReason: finishReason}, nil
+}
+
+func (u *openAIUsage) toAIUsage() models.AIUsage {
+	if u == nil {
+		return models.AIUsage{}
+	}
+	return models.AIUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+// openAIFinishReason normaliza o finish_reason das APIs no formato OpenAI
+func openAIFinishReason(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case "stop", "eos":
+		return models.FinishReasonStop
+	case "length", "model_length":
+		return models.FinishReasonLength
+	case "content_filter":
+		return models.FinishReasonContentFilter
+	default:
+		return models.FinishReasonOther
+	}
+}
+
+// openAIErrorMessage lê a mensagem do corpo de erro no formato OpenAI ({"error": {"message"}})
+func openAIErrorMessage(body []byte) string {
+	var result openAIErrorResponse
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+	return result.Error.Message
+}
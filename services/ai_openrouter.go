@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"net/http"
+)
+
+// openRouterProvider chama a API do OpenRouter com fallback entre os modelos do apelido
+type openRouterProvider struct{}
+
+func init() {
+	RegisterProvider(openRouterProvider{})
+}
+
+func (openRouterProvider) Name() string {
+	return "openrouter"
+}
+
+func (p openRouterProvider) Complete(ctx context.Context, req models.CompletionRequest) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "openrouter", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.complete(ctx, req, apiKey)
+	})
+}
+
+// complete faz a chamada com uma chave do pool (withAPIKey)
+func (p openRouterProvider) complete(ctx context.Context, req models.CompletionRequest, apiKey string) (*models.CompletionResponse, error) {
+	headers := openRouterHeaders(apiKey)
+
+	var lastErr error
+	for _, model := range providerModels("openrouter", req) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		status, body, err := postJSON(ctx, providerURL("openrouter", "/chat/completions"), headers, openAIChatPayload("openrouter", req, model))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if status != http.StatusOK {
+			lastErr = &ProviderStatusError{Provider: "openrouter", StatusCode: status, Message: openAIErrorMessage(body)}
+			continue
+		}
+
+		response, err := openAICompletion(p.Name(), model, body)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return response, nil
+	}
+
+	return nil, openRouterUnavailable(lastErr)
+}
+
+// Stream usa o modo `stream: true`; o fallback de modelos só vale até o stream abrir
+func (p openRouterProvider) Stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error) {
+	return withAPIKey(ctx, "openrouter", func(ctx context.Context, apiKey string) (*models.CompletionResponse, error) {
+		return p.stream(ctx, req, onToken, apiKey)
+	})
+}
+
+// stream faz o stream com uma chave do pool (withAPIKey)
+func (p openRouterProvider) stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error, apiKey string) (*models.CompletionResponse, error) {
+	headers := openRouterHeaders(apiKey)
+
+	var lastErr error
+	for _, model := range providerModels("openrouter", req) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		payload := openAIChatPayload("openrouter", req, model)
+		payload.Stream = true
+		payload.StreamOptions = &openAIStreamOptions{IncludeUsage: true}
+
+		body, err := postStream(ctx, "openrouter", providerURL("openrouter", "/chat/completions"), headers, payload, openAIErrorMessage)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		response, err := openAIStreamCompletion(p.Name(), model, body, onToken)
+		body.Close()
+		if errors.Is(err, ErrEmptyAIResponse) {
+			lastErr = err
+			continue
+		}
+		return response, err
+	}
+
+	return nil, openRouterUnavailable(lastErr)
+}
+
+// openRouterUnavailable mantém o erro do último modelo tentado para a classificação
+func openRouterUnavailable(lastErr error) error {
+	if lastErr == nil {
+		return errors.New("todos os modelos estão indisponíveis no momento")
+	}
+	return fmt.Errorf("todos os modelos estão indisponíveis no momento: %w", lastErr)
+}
+
+func openRouterHeaders(apiKey string) map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + apiKey,
+		"HTTP-Referer":  "https://lingobot-api.onrender.com",
+		"X-Title":       "Go Gin OpenRouter App",
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StreamingProvider é implementado pelos provedores com API de streaming.
+// onToken é chamado a cada trecho de texto recebido; se retornar erro o stream é interrompido.
+type StreamingProvider interface {
+	Provider
+	Stream(ctx context.Context, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error)
+}
+
+// ErrStreamInterrupted indica que o provedor falhou depois de já ter enviado tokens ao cliente,
+// quando não é mais possível trocar de provedor
+var ErrStreamInterrupted = errors.New("AI stream interrupted")
+
+// aiStreamClient não tem timeout total: a duração do stream é controlada pelo contexto
+var aiStreamClient = &http.Client{}
+
+// StreamAIWithFallback funciona como CallAIWithFallback, mas entregando o texto aos poucos.
+// A troca de provedor só acontece enquanto nenhum token foi enviado; provedores sem
+// streaming são chamados normalmente e a resposta inteira vira um único token.
+func StreamAIWithFallback(ctx context.Context, req models.CompletionRequest, requested []string, onToken func(string) error) (*models.CompletionResponse, error) {
+	chain, err := providerChain(requested)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config.GetAIConfig()
+
+	var attempts []ProviderAttempt
+	for _, p := range chain {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		sent := false
+		emit := func(token string) error {
+			if token == "" {
+				return nil
+			}
+			sent = true
+			return onToken(token)
+		}
+
+		response, err := streamWithTimeout(ctx, p, cfg.Provider(p.Name()).Timeout, req, emit)
+		if err == nil {
+			return response, nil
+		}
+		if sent {
+			return nil, fmt.Errorf("%w: %s: %w", ErrStreamInterrupted, p.Name(), err)
+		}
+		attempts = append(attempts, ProviderAttempt{Provider: p.Name(), Err: err})
+	}
+
+	return nil, fallbackError(attempts)
+}
+
+// streamWithTimeout chama o provedor (em streaming, se suportado) através do circuit breaker
+// e registra o consumo
+func streamWithTimeout(ctx context.Context, p Provider, timeout time.Duration, req models.CompletionRequest, onToken func(string) error) (*models.CompletionResponse, error) {
+	if err := validateGeneration(p.Name(), req.GenerationParams); err != nil {
+		return nil, err
+	}
+
+	response, err := callWithBreaker(ctx, p, func(ctx context.Context) (*models.CompletionResponse, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		sp, ok := p.(StreamingProvider)
+		if !ok {
+			response, err := p.Complete(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return response, onToken(response.Text)
+		}
+
+		return sp.Stream(ctx, req, onToken)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	RegistrarUsoIA(ctx, req, response)
+	return response, nil
+}
+
+// postStream envia o payload como JSON e devolve a resposta aberta para leitura do stream.
+// Se o status não for 200 o corpo de erro é lido com errorMessage (formato do fornecedor)
+// e um ProviderStatusError é retornado.
+func postStream(ctx context.Context, provider, url string, headers map[string]string, payload interface{}, errorMessage func([]byte) string) (io.ReadCloser, error) {
+	jsonData, err := utils.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// O retry só vale até o stream abrir; depois disso os tokens já foram entregues
+	resp, err := doWithRetry(ctx, aiStreamClient, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "text/event-stream")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		resp.Body.Close()
+		return nil, &ProviderStatusError{Provider: provider, StatusCode: resp.StatusCode, Message: errorMessage(body)}
+	}
+
+	return resp.Body, nil
+}
+
+// readSSE lê um stream Server-Sent Events e chama onData com o conteúdo de cada linha `data:`.
+// O marcador `[DONE]` das APIs compatíveis com OpenAI encerra a leitura.
+func readSSE(body io.Reader, onData func([]byte) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			return nil
+		}
+
+		if err := onData([]byte(data)); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/utils"
+	"strings"
+)
+
+// structuredMaxAttempts limita as tentativas do ciclo validar-e-repetir
+const structuredMaxAttempts = 3
+
+// ErrInvalidStructuredOutput é retornado quando a IA não gera um JSON válido para o schema
+var ErrInvalidStructuredOutput = errors.New("AI did not return valid JSON for the schema")
+
+// CallAIStructured pede à IA uma resposta em JSON que siga o schema informado.
+// Provedores com saída estruturada nativa recebem o schema em req.ResponseSchema; em todos
+// os casos a resposta é validada e, se inválida, o erro é devolvido à IA para nova tentativa.
+func CallAIStructured(ctx context.Context, req models.CompletionRequest, schema map[string]interface{}, requested []string) (*models.StructuredAIResponse, error) {
+	schemaJSON, err := utils.MarshalString(schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema inválido: %v", err)
+	}
+
+	instructions := "Respond only with a JSON value that validates against this JSON Schema, with no markdown and no extra text:\n" + schemaJSON
+	if req.System != "" {
+		req.System += "\n\n" + instructions
+	} else {
+		req.System = instructions
+	}
+	req.ResponseSchema = schema
+
+	var lastErr error
+	for attempt := 1; attempt <= structuredMaxAttempts; attempt++ {
+		response, err := CallAIWithFallback(ctx, req, requested)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := parseStructuredOutput(response.Text, schema)
+		if err == nil {
+			return &models.StructuredAIResponse{
+				Data:     data,
+				Provider: response.Provider,
+				Model:    response.Model,
+				Attempts: attempt,
+			}, nil
+		}
+		lastErr = err
+
+		// Devolve a resposta inválida e o erro para a IA corrigir na próxima tentativa
+		req.History = append(req.History,
+			models.AIMessage{Role: models.RoleUser, Content: req.Prompt},
+			models.AIMessage{Role: models.RoleAssistant, Content: response.Text},
+		)
+		req.Prompt = fmt.Sprintf("Your previous answer was invalid (%v). Reply again with only the corrected JSON.", err)
+	}
+
+	return nil, fmt.Errorf("%w: %v", ErrInvalidStructuredOutput, lastErr)
+}
+
+// parseStructuredOutput extrai o JSON da resposta (removendo cercas de markdown) e valida
+func parseStructuredOutput(text string, schema map[string]interface{}) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+		text = strings.TrimSpace(text)
+	}
+
+	var data interface{}
+	if err := utils.UnmarshalString(text, &data); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %v", err)
+	}
+
+	if err := utils.ValidateJSONSchema(schema, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// decodeStructured converte o JSON validado de CallAIStructured para a struct de destino
+func decodeStructured(data interface{}, v interface{}) error {
+	raw, err := utils.Marshal(data)
+	if err == nil {
+		err = utils.Unmarshal(raw, v)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidStructuredOutput, err)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCotaIAEsgotada é retornado quando o usuário não tem battery nem tokens para a requisição
+	ErrCotaIAEsgotada = errors.New("bateria esgotada: aguarde a recarga ou use tokens para continuar usando a IA")
+	// ErrPlanoInvalido é retornado ao definir um plano que não está em regrasPlano
+	ErrPlanoInvalido = errors.New("plano inválido")
+	// ErrUsuarioNaoEncontrado é retornado quando o usuário não existe
+	ErrUsuarioNaoEncontrado = errors.New("usuário não encontrado")
+)
+
+// bateriaRecargaIntervalo é o tempo para a battery recuperar uma carga (até models.BatteryMax)
+const bateriaRecargaIntervalo = time.Hour
+
+// regraPlano define quanto cada requisição de IA custa em um plano
+type regraPlano struct {
+	BatteryPorRequisicao int  // débito normal na battery
+	TokensPorRequisicao  int  // alternativa quando a battery acaba (0 = sem alternativa)
+	ModelosPremium       bool // libera os modelos marcados como premium no catálogo
+}
+
+// regrasPlano são as regras de cobrança por plano (usuario_economia.plano).
+// Planos desconhecidos seguem a regra do "free".
+var regrasPlano = map[string]regraPlano{
+	"free":    {BatteryPorRequisicao: 1, TokensPorRequisicao: 1},
+	"premium": {ModelosPremium: true}, // ilimitado, apenas medido
+}
+
+// regraDoPlano retorna a regra do plano (planos desconhecidos seguem o "free")
+func regraDoPlano(plano string) regraPlano {
+	regra, ok := regrasPlano[plano]
+	if !ok {
+		return regrasPlano["free"]
+	}
+	return regra
+}
+
+// DefinirPlanoRequest - Corpo do PUT /admin/usuarios/:id/plano
+type DefinirPlanoRequest struct {
+	Plano string `json:"plano" binding:"required"`
+}
+
+// DefinirPlano altera o plano do usuário (administração ou confirmação de assinatura).
+// Só aceita os planos de regrasPlano; o plano é o que libera os modelos premium.
+func DefinirPlano(usuarioID int, plano string) (string, error) {
+	plano = strings.ToLower(strings.TrimSpace(plano))
+	if _, ok := regrasPlano[plano]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrPlanoInvalido, plano)
+	}
+
+	alterado, err := repositories.UpdatePlano(usuarioID, plano)
+	if err != nil {
+		return "", errors.New("erro ao atualizar plano")
+	}
+	if !alterado {
+		return "", ErrUsuarioNaoEncontrado
+	}
+
+	return plano, nil
+}
+
+type usuarioIAKey struct{}
+
+// ContextWithUsuarioIA marca o contexto com o usuário que paga pelas chamadas de IA.
+// Com ele, cada resposta bem-sucedida dos provedores tem o consumo gravado em ai_uso.
+func ContextWithUsuarioIA(ctx context.Context, usuarioID int) context.Context {
+	return context.WithValue(ctx, usuarioIAKey{}, usuarioID)
+}
+
+// usuarioIAFromContext retorna o usuário marcado com ContextWithUsuarioIA
+func usuarioIAFromContext(ctx context.Context) (int, bool) {
+	usuarioID, ok := ctx.Value(usuarioIAKey{}).(int)
+	return usuarioID, ok
+}
+
+// ReservarCotaIA debita o custo de uma requisição de IA de acordo com o plano do usuário.
+// O débito é feito antes da chamada para que requisições simultâneas não passem do saldo.
+func ReservarCotaIA(usuarioID int) (*models.ReservaCotaIA, error) {
+	economia, err := economiaRecarregada(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	regra := regraDoPlano(economia.Plano)
+
+	reserva := &models.ReservaCotaIA{UsuarioID: usuarioID}
+	switch {
+	case economia.Battery >= regra.BatteryPorRequisicao:
+		reserva.Battery = regra.BatteryPorRequisicao
+	case regra.TokensPorRequisicao > 0 && economia.Tokens >= regra.TokensPorRequisicao:
+		reserva.Tokens = regra.TokensPorRequisicao
+	default:
+		return nil, ErrCotaIAEsgotada
+	}
+
+	if reserva.Battery == 0 && reserva.Tokens == 0 {
+		return reserva, nil
+	}
+
+	debitado, err := repositories.DebitarEconomia(usuarioID, reserva.Battery, reserva.Tokens)
+	if err != nil {
+		return nil, errors.New("erro ao debitar bateria")
+	}
+	if !debitado {
+		// Outra requisição consumiu o saldo entre a leitura e o débito
+		return nil, ErrCotaIAEsgotada
+	}
+
+	return reserva, nil
+}
+
+// economiaRecarregada busca a economia do usuário já com as cargas que a battery recuperou
+func economiaRecarregada(usuarioID int) (*models.UsuarioEconomia, error) {
+	economia, err := repositories.GetUsuarioEconomia(usuarioID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar economia do usuário")
+	}
+
+	cargas, recargaEm := recargaBateria(economia.Battery, economia.BatteryRecargaEm, time.Now().UTC())
+	if recargaEm.Equal(economia.BatteryRecargaEm) {
+		return economia, nil
+	}
+
+	recarregado, err := repositories.RecarregarBateria(usuarioID, cargas, economia.BatteryRecargaEm, recargaEm)
+	if err != nil {
+		// Sem a recarga o usuário segue com o saldo atual
+		log.Printf("❌ Erro ao recarregar battery do usuário %d: %v", usuarioID, err)
+		return economia, nil
+	}
+	if !recarregado {
+		// Outra requisição recarregou entre a leitura e a atualização
+		economia, err = repositories.GetUsuarioEconomia(usuarioID)
+		if err != nil {
+			return nil, errors.New("erro ao buscar economia do usuário")
+		}
+		return economia, nil
+	}
+
+	economia.Battery = min(economia.Battery+cargas, models.BatteryMax)
+	economia.BatteryRecargaEm = recargaEm
+	return economia, nil
+}
+
+// recargaBateria calcula quantas cargas a battery recuperou desde recargaEm e o novo início
+// da contagem. A contagem só corre com a battery abaixo do máximo: cheia, ela recomeça.
+func recargaBateria(battery int, recargaEm, agora time.Time) (int, time.Time) {
+	decorrido := agora.Sub(recargaEm)
+	if decorrido < bateriaRecargaIntervalo {
+		return 0, recargaEm
+	}
+
+	if battery >= models.BatteryMax {
+		return 0, agora
+	}
+
+	cargas := int(decorrido / bateriaRecargaIntervalo)
+	if battery+cargas >= models.BatteryMax {
+		return models.BatteryMax - battery, agora
+	}
+
+	return cargas, recargaEm.Add(time.Duration(cargas) * bateriaRecargaIntervalo)
+}
+
+// EstornarCotaIA devolve o débito de uma requisição de IA que falhou
+func EstornarCotaIA(reserva *models.ReservaCotaIA) {
+	if reserva == nil || (reserva.Battery == 0 && reserva.Tokens == 0) {
+		return
+	}
+
+	if err := repositories.CreditarEconomia(reserva.UsuarioID, reserva.Battery, reserva.Tokens); err != nil {
+		log.Printf("❌ Erro ao estornar cota de IA do usuário %d: %v", reserva.UsuarioID, err)
+	}
+}
+
+// RegistrarUsoIA grava o consumo de tokens da resposta para o usuário do contexto.
+// Sem usuário no contexto (ex.: chamadas internas) nada é gravado.
+// Se o provedor não informou o uso, ele é estimado (~4 caracteres por token).
+func RegistrarUsoIA(ctx context.Context, req models.CompletionRequest, response *models.CompletionResponse) {
+	usuarioID, ok := usuarioIAFromContext(ctx)
+	if !ok || response == nil {
+		return
+	}
+
+	uso := &models.AIUso{
+		UsuarioID:        usuarioID,
+		Provider:         response.Provider,
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+	}
+	if response.Model != "" {
+		uso.Model = &response.Model
+	}
+
+	if uso.PromptTokens == 0 && uso.CompletionTokens == 0 {
+		uso.Estimado = true
+		uso.PromptTokens = estimarTokens(req.System) + estimarTokens(req.Prompt)
+		for _, m := range req.History {
+			uso.PromptTokens += estimarTokens(m.Content)
+		}
+		uso.CompletionTokens = estimarTokens(response.Text)
+	}
+
+	if err := repositories.InsertAIUso(uso); err != nil {
+		log.Printf("❌ Erro ao registrar uso de IA: %v", err)
+	}
+}
+
+// ResumoUsoIA retorna o saldo atual (com a próxima recarga da battery) e o consumo por
+// provedor nos últimos `dias`
+func ResumoUsoIA(usuarioID, dias int) (*models.AIUsoResumo, error) {
+	economia, err := economiaRecarregada(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	desde := time.Now().UTC().AddDate(0, 0, -dias) // created_at é TIMESTAMP em UTC
+	providers, err := repositories.GetAIUsoPorProvider(usuarioID, desde)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar uso de IA: %v", err)
+	}
+
+	resumo := &models.AIUsoResumo{
+		Plano:     economia.Plano,
+		Battery:   economia.Battery,
+		Tokens:    economia.Tokens,
+		Desde:     desde,
+		Providers: providers,
+	}
+	if economia.Battery < models.BatteryMax {
+		proxima := economia.BatteryRecargaEm.Add(bateriaRecargaIntervalo)
+		resumo.ProximaRecarga = &proxima
+	}
+
+	return resumo, nil
+}
+
+// estimarTokens estima o número de tokens de um texto (~4 caracteres por token)
+func estimarTokens(text string) int {
+	runes := len([]rune(text))
+	if runes == 0 {
+		return 0
+	}
+	return (runes + 3) / 4
+}
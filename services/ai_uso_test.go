@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"lingobotAPI-GO/models"
+	"testing"
+	"time"
+)
+
+func TestRecargaBateria(t *testing.T) {
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		battery       int
+		recargaEm     time.Time
+		wantCargas    int
+		wantRecargaEm time.Time
+	}{
+		{
+			name:          "antes do intervalo nada muda",
+			battery:       3,
+			recargaEm:     agora.Add(-bateriaRecargaIntervalo + time.Second),
+			wantCargas:    0,
+			wantRecargaEm: agora.Add(-bateriaRecargaIntervalo + time.Second),
+		},
+		{
+			name:          "sobra do intervalo fica para a próxima carga",
+			battery:       3,
+			recargaEm:     agora.Add(-2*bateriaRecargaIntervalo - time.Minute),
+			wantCargas:    2,
+			wantRecargaEm: agora.Add(-time.Minute),
+		},
+		{
+			name:          "recarga para no máximo e recomeça a contagem",
+			battery:       models.BatteryMax - 1,
+			recargaEm:     agora.Add(-5 * bateriaRecargaIntervalo),
+			wantCargas:    1,
+			wantRecargaEm: agora,
+		},
+		{
+			name:          "cheia recomeça a contagem",
+			battery:       models.BatteryMax,
+			recargaEm:     agora.Add(-3 * bateriaRecargaIntervalo),
+			wantCargas:    0,
+			wantRecargaEm: agora,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cargas, recargaEm := recargaBateria(tt.battery, tt.recargaEm, agora)
+
+			if cargas != tt.wantCargas {
+				t.Errorf("cargas = %d, want %d", cargas, tt.wantCargas)
+			}
+			if !recargaEm.Equal(tt.wantRecargaEm) {
+				t.Errorf("recargaEm = %v, want %v", recargaEm, tt.wantRecargaEm)
+			}
+		})
+	}
+}
+
+func TestDefinirPlanoInvalido(t *testing.T) {
+	// Planos fora de regrasPlano são recusados antes de chegar ao banco
+	if _, err := DefinirPlano(1, "gold"); !errors.Is(err, ErrPlanoInvalido) {
+		t.Errorf("DefinirPlano() error = %v, want %v", err, ErrPlanoInvalido)
+	}
+}
@@ -3,21 +3,58 @@ package services
 import (
 	"errors"
 	"fmt"
+	"lingobotAPI-GO/models"
 	"lingobotAPI-GO/repositories"
 	"lingobotAPI-GO/utils"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Erros do /token/refresh
+var (
+	ErrRefreshTokenInvalido    = errors.New("refresh token inválido ou expirado")
+	ErrRefreshTokenReutilizado = errors.New("refresh token já utilizado: sessão encerrada, faça login novamente")
 )
 
+// ErrTokenNaoFresh é retornado quando uma operação sensível recebe um token renovado pelo /token/refresh
+var ErrTokenNaoFresh = errors.New("operação exige login recente: faça login novamente")
+
+// RequireFreshToken exige um token emitido pelo login com senha (não renovado pelo /token/refresh).
+// Vale para as operações sensíveis, como trocar o email ou a senha.
+func RequireFreshToken(claims *utils.Claims) error {
+	if claims == nil || !claims.Fresh {
+		return ErrTokenNaoFresh
+	}
+	return nil
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Cookie   bool   `json:"cookie"` // cliente web: tokens em cookies HttpOnly (exige AUTH_COOKIES)
 }
 
+// Na sessão por cookie os tokens não vão no corpo; vai só o csrf_token para o X-CSRF-Token
 type LoginResponse struct {
 	Mensagem     string `json:"mensagem"`
-	AccessToken  string `json:"access_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
+}
+
+// Na sessão por cookie o refresh token vem do cookie
+type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type RefreshTokenResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
+}
+
 // Login realiza o login do usuário e retorna tokens JWT
 func Login(req LoginRequest) (*LoginResponse, error) {
 	fmt.Printf("🔍 Tentando login para: %s\n", req.Email)
@@ -53,14 +90,18 @@ func Login(req LoginRequest) (*LoginResponse, error) {
 		"id": usuarioCompleto.Usuario.ID,
 	}
 
-	// Gera os tokens
-	accessToken, err := utils.GenerateAccessToken(usuarioCompleto.Usuario.ID, userData)
+	// Gera os tokens (o access token do login é fresh)
+	accessToken, err := utils.GenerateFreshAccessToken(usuarioCompleto.Usuario.ID, userData)
 	if err != nil {
 		fmt.Printf("❌ Erro ao gerar access token: %v\n", err)
 		return nil, errors.New("erro ao gerar token de acesso")
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(usuarioCompleto.Usuario.ID)
+	// Cada login abre uma nova família de refresh tokens
+	refreshToken, rt, err := gerarRefreshToken(usuarioCompleto.Usuario.ID, uuid.New().String())
+	if err == nil {
+		err = repositories.InsertRefreshToken(rt)
+	}
 	if err != nil {
 		fmt.Printf("❌ Erro ao gerar refresh token: %v\n", err)
 		return nil, errors.New("erro ao gerar token de refresh")
@@ -74,3 +115,76 @@ func Login(req LoginRequest) (*LoginResponse, error) {
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// RefreshToken troca um refresh token válido por um access token novo e um refresh token
+// rotacionado da mesma família. Reapresentar um refresh token já usado indica roubo:
+// a família inteira é revogada e o usuário precisa fazer login de novo.
+func RefreshToken(req RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	claims, err := utils.ValidateToken(req.RefreshToken)
+	if err != nil || claims.Type != "refresh" || claims.Family == "" {
+		return nil, ErrRefreshTokenInvalido
+	}
+
+	refreshToken, novo, err := gerarRefreshToken(claims.Sub, claims.Family)
+	if err != nil {
+		return nil, errors.New("erro ao gerar token de refresh")
+	}
+
+	rotacionado, err := repositories.RotacionarRefreshToken(claims.JTI, claims.Sub, novo)
+	if err != nil {
+		return nil, errors.New("erro ao renovar token")
+	}
+	if !rotacionado {
+		return nil, refreshTokenRecusado(claims)
+	}
+
+	accessToken, err := utils.GenerateAccessToken(claims.Sub, map[string]interface{}{"id": claims.Sub})
+	if err != nil {
+		return nil, errors.New("erro ao gerar token de acesso")
+	}
+
+	return &RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// refreshTokenRecusado explica por que o token não pôde ser rotacionado, revogando a
+// família quando o token já tinha sido usado
+func refreshTokenRecusado(claims *utils.Claims) error {
+	rt, err := repositories.GetRefreshToken(claims.JTI)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrRefreshTokenInvalido
+	}
+	if err != nil {
+		return errors.New("erro ao renovar token")
+	}
+
+	if rt.UsadoEm == nil || rt.UsuarioID != claims.Sub {
+		return ErrRefreshTokenInvalido
+	}
+
+	revogados, err := repositories.RevogarFamiliaRefreshToken(rt.Familia)
+	if err != nil {
+		log.Printf("❌ Erro ao revogar família de refresh token %s: %v", rt.Familia, err)
+	} else {
+		log.Printf("⚠️ Refresh token reutilizado (usuário %d): %d token(s) da família %s revogado(s)", rt.UsuarioID, revogados, rt.Familia)
+	}
+	return ErrRefreshTokenReutilizado
+}
+
+// gerarRefreshToken assina um refresh token da família e monta o registro a ser gravado
+func gerarRefreshToken(usuarioID int, familia string) (string, *models.RefreshToken, error) {
+	token, claims, err := utils.GenerateRefreshToken(usuarioID, familia)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rt := &models.RefreshToken{
+		JTI:       claims.JTI,
+		Familia:   familia,
+		UsuarioID: usuarioID,
+		ExpiraEm:  claims.ExpiresAt.Time.UTC(), // colunas TIMESTAMP guardam UTC
+	}
+	return token, rt, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/repositories"
+	"lingobotAPI-GO/utils"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const schemaRefreshToken = `
+	CREATE TEMP TABLE refresh_token (
+		jti         UUID PRIMARY KEY,
+		familia     UUID NOT NULL,
+		usuario_id  INTEGER NOT NULL,
+		expira_em   TIMESTAMP NOT NULL,
+		usado_em    TIMESTAMP,
+		revogado_em TIMESTAMP,
+		created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+func TestRequireFreshToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *utils.Claims
+		want   error
+	}{
+		{name: "token do login", claims: &utils.Claims{Sub: 1, Fresh: true}, want: nil},
+		{name: "token renovado", claims: &utils.Claims{Sub: 1}, want: ErrTokenNaoFresh},
+		{name: "sem claims", claims: nil, want: ErrTokenNaoFresh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RequireFreshToken(tt.claims); !errors.Is(err, tt.want) {
+				t.Errorf("RequireFreshToken() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlterarSenhaExigeTokenFresh(t *testing.T) {
+	// Recusada antes de buscar o usuário no banco
+	err := AlterarSenha(&utils.Claims{Sub: 1}, AlterarSenhaRequest{SenhaAtual: "antiga", NovaSenha: "nova"})
+	if !errors.Is(err, ErrTokenNaoFresh) {
+		t.Errorf("AlterarSenha() = %v, want %v", err, ErrTokenNaoFresh)
+	}
+}
+
+func TestRefreshTokenReusoRevogaFamilia(t *testing.T) {
+	bancoDeTeste(t, schemaRefreshToken)
+
+	familia := uuid.NewString()
+	original, rt, err := gerarRefreshToken(1, familia)
+	if err != nil {
+		t.Fatalf("gerarRefreshToken() error = %v", err)
+	}
+	if err := repositories.InsertRefreshToken(rt); err != nil {
+		t.Fatalf("InsertRefreshToken() error = %v", err)
+	}
+
+	rotacionado, err := RefreshToken(RefreshTokenRequest{RefreshToken: original})
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	// Reapresentar o token já usado revoga a família inteira
+	if _, err := RefreshToken(RefreshTokenRequest{RefreshToken: original}); !errors.Is(err, ErrRefreshTokenReutilizado) {
+		t.Fatalf("RefreshToken() reutilizado = %v, want %v", err, ErrRefreshTokenReutilizado)
+	}
+
+	// ...inclusive o token emitido pela rotação legítima
+	if _, err := RefreshToken(RefreshTokenRequest{RefreshToken: rotacionado.RefreshToken}); !errors.Is(err, ErrRefreshTokenInvalido) {
+		t.Fatalf("RefreshToken() da família revogada = %v, want %v", err, ErrRefreshTokenInvalido)
+	}
+
+	var ativos int
+	query := `SELECT COUNT(*) FROM refresh_token WHERE familia = $1 AND revogado_em IS NULL`
+	if err := config.DB.QueryRow(context.Background(), query, familia).Scan(&ativos); err != nil {
+		t.Fatalf("erro ao contar tokens da família: %v", err)
+	}
+	if ativos != 0 {
+		t.Errorf("tokens ativos na família = %d, want 0", ativos)
+	}
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"lingobotAPI-GO/config"
+	"lingobotAPI-GO/models"
+	"sync"
+	"time"
+)
+
+// Estados do circuit breaker
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+const (
+	breakerFailureThreshold = 5                // falhas seguidas para abrir o circuito
+	breakerCooldown         = 30 * time.Second // tempo aberto antes de testar de novo
+	breakerWindowSize       = 20               // chamadas consideradas na taxa de erro
+)
+
+// ErrCircuitOpen é retornado quando o provedor está com o circuito aberto
+var ErrCircuitOpen = errors.New("AI provider circuit open")
+
+// circuitBreaker acompanha a saúde de um provedor.
+// Closed: chamadas passam normalmente. Open: chamadas são recusadas até o cooldown.
+// Half-open: uma única chamada de teste decide se volta para closed ou open.
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	probeInFlight       bool
+	window              []bool // resultados recentes (true = erro)
+	next                int
+	lastErrorCategory   string
+	lastErrorAt         time.Time
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = map[string]*circuitBreaker{}
+)
+
+// breakerFor retorna (criando se preciso) o circuit breaker do provedor
+func breakerFor(name string) *circuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	b, ok := breakers[name]
+	if !ok {
+		b = &circuitBreaker{state: BreakerClosed}
+		breakers[name] = b
+	}
+	return b
+}
+
+// allow informa se uma chamada pode ser feita agora
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < breakerCooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probeInFlight = true
+		return true
+	case BreakerHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record registra o resultado de uma chamada
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := err != nil
+	if len(b.window) < breakerWindowSize {
+		b.window = append(b.window, failed)
+	} else {
+		b.window[b.next] = failed
+		b.next = (b.next + 1) % breakerWindowSize
+	}
+
+	wasProbe := b.state == BreakerHalfOpen
+	b.probeInFlight = false
+
+	if !failed {
+		b.consecutiveFailures = 0
+		b.state = BreakerClosed
+		return
+	}
+
+	b.consecutiveFailures++
+	b.lastErrorCategory = ClassifyAIError(err)
+	b.lastErrorAt = time.Now()
+
+	if wasProbe || b.consecutiveFailures >= breakerFailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// release libera a chamada de teste sem registrar resultado (ex.: cliente cancelou)
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probeInFlight = false
+	}
+}
+
+// snapshot devolve o estado atual para o /health
+func (b *circuitBreaker) snapshot(name string) models.ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == BreakerOpen && time.Since(b.openedAt) >= breakerCooldown {
+		state = BreakerHalfOpen
+	}
+
+	errorsInWindow := 0
+	for _, failed := range b.window {
+		if failed {
+			errorsInWindow++
+		}
+	}
+
+	health := models.ProviderHealth{
+		Name:                name,
+		State:               state,
+		RecentCalls:         len(b.window),
+		ConsecutiveFailures: b.consecutiveFailures,
+		LastErrorCategory:   b.lastErrorCategory,
+	}
+	if len(b.window) > 0 {
+		health.ErrorRate = float64(errorsInWindow) / float64(len(b.window))
+	}
+	if !b.lastErrorAt.IsZero() {
+		lastErrorAt := b.lastErrorAt
+		health.LastErrorAt = &lastErrorAt
+	}
+	if state == BreakerOpen {
+		retryAt := b.openedAt.Add(breakerCooldown)
+		health.RetryAt = &retryAt
+	}
+
+	return health
+}
+
+// callWithBreaker executa a chamada ao provedor passando pelo circuit breaker
+func callWithBreaker(ctx context.Context, p Provider, call func(context.Context) (*models.CompletionResponse, error)) (*models.CompletionResponse, error) {
+	b := breakerFor(p.Name())
+	if !b.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	response, err := call(ctx)
+
+	// Cancelamento pelo cliente e erros do próprio pedido (conteúdo bloqueado, requisição
+	// inválida, chave ausente) não dizem nada sobre a saúde do provedor
+	if err != nil && (errors.Is(ctx.Err(), context.Canceled) || !breakerFailure(err)) {
+		b.release()
+		return nil, err
+	}
+
+	b.record(err)
+	return response, err
+}
+
+// breakerFailure informa se o erro indica indisponibilidade do provedor (rede, timeout, 5xx, 429)
+func breakerFailure(err error) bool {
+	switch ClassifyAIError(err) {
+	case ErrorCategoryTimeout, ErrorCategoryNetwork, ErrorCategoryUnavailable, ErrorCategoryRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderHealth retorna o estado de saúde de todos os provedores registrados
+func ProviderHealth() []models.ProviderHealth {
+	cfg := config.GetAIConfig()
+
+	var result []models.ProviderHealth
+	for _, p := range ListProviders() {
+		health := breakerFor(p.Name()).snapshot(p.Name())
+		health.Enabled = cfg.Provider(p.Name()).Enabled
+		result = append(result, health)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// historicoMaximo limita quantas mensagens anteriores são reenviadas ao provedor
+const historicoMaximo = 20
+
+// ErrConversaNaoEncontrada é retornado quando a conversa não existe ou é de outro usuário
+var ErrConversaNaoEncontrada = errors.New("conversa não encontrada")
+
+// CriarConversa abre uma nova conversa para o usuário
+func CriarConversa(usuarioID int, req models.CriarConversaRequest) (*models.Conversa, error) {
+	if req.Titulo != nil {
+		titulo := strings.TrimSpace(*req.Titulo)
+		if titulo == "" {
+			req.Titulo = nil
+		} else {
+			req.Titulo = &titulo
+		}
+	}
+
+	conversa := &models.Conversa{
+		UsuarioID: usuarioID,
+		Titulo:    req.Titulo,
+	}
+
+	if err := repositories.InsertConversa(conversa); err != nil {
+		return nil, errors.New("erro ao criar conversa")
+	}
+
+	return conversa, nil
+}
+
+// ListarConversas retorna as conversas do usuário
+func ListarConversas(usuarioID int) ([]models.Conversa, error) {
+	conversas, err := repositories.GetConversasByUsuario(usuarioID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar conversas")
+	}
+	return conversas, nil
+}
+
+// GetConversa retorna a conversa com todas as mensagens
+func GetConversa(usuarioID, conversaID int) (*models.ConversaDetalhe, error) {
+	conversa, err := buscarConversa(usuarioID, conversaID)
+	if err != nil {
+		return nil, err
+	}
+
+	mensagens, err := repositories.GetMensagensConversa(conversaID, 0)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mensagens")
+	}
+
+	return &models.ConversaDetalhe{
+		Conversa:  *conversa,
+		Mensagens: mensagens,
+	}, nil
+}
+
+// EnviarMensagem envia a mensagem do usuário para a IA junto com o histórico da conversa
+// (e a persona, se informada) e grava a pergunta e a resposta
+func EnviarMensagem(ctx context.Context, usuarioID, conversaID int, req models.EnviarMensagemRequest) (*models.ConversaMensagem, error) {
+	if _, err := buscarConversa(usuarioID, conversaID); err != nil {
+		return nil, err
+	}
+
+	anteriores, err := repositories.GetMensagensConversa(conversaID, historicoMaximo)
+	if err != nil {
+		return nil, errors.New("erro ao buscar mensagens")
+	}
+
+	history := make([]models.AIMessage, 0, len(anteriores))
+	for _, m := range anteriores {
+		history = append(history, models.AIMessage{Role: m.Role, Content: m.Content})
+	}
+
+	system, err := BuildSystemPrompt(usuarioID, req.Persona)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Model != "" {
+		if err := AutorizarModeloIA(usuarioID, req.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := CallAIWithFallback(ctx, models.CompletionRequest{System: system, Prompt: req.Text, History: history, Model: req.Model, GenerationParams: req.GenerationParams}, req.Providers)
+	if err != nil {
+		return nil, err
+	}
+
+	pergunta := &models.ConversaMensagem{Role: models.RoleUser, Content: req.Text}
+	resposta := &models.ConversaMensagem{Role: models.RoleAssistant, Content: response.Text, Provider: &response.Provider}
+
+	if err := repositories.InsertMensagensConversa(conversaID, pergunta, resposta); err != nil {
+		return nil, errors.New("erro ao salvar mensagens")
+	}
+
+	return resposta, nil
+}
+
+// ApagarConversa remove a conversa e suas mensagens
+func ApagarConversa(usuarioID, conversaID int) error {
+	apagada, err := repositories.DeleteConversa(usuarioID, conversaID)
+	if err != nil {
+		return errors.New("erro ao apagar conversa")
+	}
+	if !apagada {
+		return ErrConversaNaoEncontrada
+	}
+	return nil
+}
+
+// buscarConversa garante que a conversa existe e pertence ao usuário
+func buscarConversa(usuarioID, conversaID int) (*models.Conversa, error) {
+	conversa, err := repositories.GetConversa(usuarioID, conversaID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrConversaNaoEncontrada
+	}
+	if err != nil {
+		return nil, errors.New("erro ao buscar conversa")
+	}
+	return conversa, nil
+}
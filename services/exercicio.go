@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrExerciseTypeNotFound é retornado quando o tipo de exercício pedido não existe
+var ErrExerciseTypeNotFound = errors.New("exercise type not found")
+
+// exerciseType é um tipo de exercício com schema e instruções prontos
+type exerciseType struct {
+	instrucoes string
+	schema     map[string]interface{}
+}
+
+// exerciseTypes é o catálogo de exercícios aceitos em `exercise_type`
+var exerciseTypes = map[string]exerciseType{
+	"multiple-choice": {
+		instrucoes: "Create multiple-choice questions about the student's request. Each question has exactly four options and answer_index points to the correct one (0-3).",
+		schema: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"questions"},
+			"properties": map[string]interface{}{
+				"questions": map[string]interface{}{
+					"type":     "array",
+					"minItems": 1,
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"question", "options", "answer_index", "explanation"},
+						"properties": map[string]interface{}{
+							"question":     map[string]interface{}{"type": "string", "minLength": 1},
+							"options":      map[string]interface{}{"type": "array", "minItems": 4, "maxItems": 4, "items": map[string]interface{}{"type": "string"}},
+							"answer_index": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 3},
+							"explanation":  map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	},
+	"fill-in-the-blank": {
+		instrucoes: "Create fill-in-the-blank sentences about the student's request. Mark the blank with ___ in the sentence and give the missing word(s) in answer.",
+		schema: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"items"},
+			"properties": map[string]interface{}{
+				"items": map[string]interface{}{
+					"type":     "array",
+					"minItems": 1,
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"sentence", "answer"},
+						"properties": map[string]interface{}{
+							"sentence": map[string]interface{}{"type": "string", "minLength": 1},
+							"answer":   map[string]interface{}{"type": "string", "minLength": 1},
+							"hint":     map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	},
+	"sentence-correction": {
+		instrucoes: "Correct the student's sentence. List every mistake with the wrong excerpt, the correction and a short explanation. Use an empty list if the sentence is already correct.",
+		schema: map[string]interface{}{
+			"type":     "object",
+			"required": []interface{}{"original", "corrected", "mistakes"},
+			"properties": map[string]interface{}{
+				"original":  map[string]interface{}{"type": "string"},
+				"corrected": map[string]interface{}{"type": "string"},
+				"mistakes": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"wrong", "right", "explanation"},
+						"properties": map[string]interface{}{
+							"wrong":       map[string]interface{}{"type": "string"},
+							"right":       map[string]interface{}{"type": "string"},
+							"explanation": map[string]interface{}{"type": "string"},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// ExerciseTypes retorna os nomes dos tipos de exercício disponíveis
+func ExerciseTypes() []string {
+	names := make([]string, 0, len(exerciseTypes))
+	for name := range exerciseTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExerciseSchema retorna o schema e as instruções de um tipo de exercício
+func ExerciseSchema(name string) (map[string]interface{}, string, error) {
+	exercise, ok := exerciseTypes[name]
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrExerciseTypeNotFound, name)
+	}
+	return exercise.schema, exercise.instrucoes, nil
+}
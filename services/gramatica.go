@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pontos de Writing por frase corrigida: frase sem erros vale mais, mas toda prática conta
+const (
+	writingPontosSemErros = 3
+	writingPontosComErros = 1
+)
+
+// grammarCategories são as categorias aceitas em `category` (na ordem do enum do schema)
+var grammarCategories = []interface{}{
+	models.GrammarCategoryTense,
+	models.GrammarCategoryAgreement,
+	models.GrammarCategorySpelling,
+	models.GrammarCategoryWordOrder,
+	models.GrammarCategoryOther,
+}
+
+// grammarSchema é o formato pedido à IA. As posições não vêm da IA: são calculadas
+// localizando `original` na frase do aluno.
+var grammarSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"corrected", "edits"},
+	"properties": map[string]interface{}{
+		"corrected": map[string]interface{}{"type": "string", "minLength": 1},
+		"edits": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"original", "replacement", "category", "explanation"},
+				"properties": map[string]interface{}{
+					"original":    map[string]interface{}{"type": "string", "minLength": 1},
+					"replacement": map[string]interface{}{"type": "string"},
+					"category":    map[string]interface{}{"type": "string", "enum": grammarCategories},
+					"explanation": map[string]interface{}{"type": "string", "minLength": 1},
+				},
+			},
+		},
+	},
+}
+
+// grammarOutput é a resposta da IA já validada contra o grammarSchema
+type grammarOutput struct {
+	Corrected string `json:"corrected"`
+	Edits     []struct {
+		Original    string `json:"original"`
+		Replacement string `json:"replacement"`
+		Category    string `json:"category"`
+		Explanation string `json:"explanation"`
+	} `json:"edits"`
+}
+
+// CorrigirGramatica corrige a frase do aluno no idioma que ele estuda (Learning), devolve as
+// edições com posição e categoria e soma os pontos da prática à skill Writing
+func CorrigirGramatica(ctx context.Context, usuarioID int, req models.GrammarCheckRequest) (*models.GrammarCheckResponse, error) {
+	if req.Model != "" {
+		if err := AutorizarModeloIA(usuarioID, req.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar progresso do usuário")
+	}
+
+	language := languageName(progresso.Learning)
+	difficulty := progresso.Difficulty
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+
+	system := fmt.Sprintf(`You are Lingobot, a %[1]s teacher correcting a student's writing.
+The student is learning %[1]s at %[2]s difficulty. Correct only real mistakes and keep the student's meaning and style.
+For each mistake, "original" must be copied exactly from the student's text; for a missing word, include the neighbouring word in "original" and "replacement".
+Categories: tense, agreement, spelling, word_order, or other for anything else. Explanations are one short sentence a %[2]s learner understands.
+If the text is already correct, return it unchanged in "corrected" with an empty "edits" list.`, language, difficulty)
+
+	response, err := CallAIStructured(ctx, models.CompletionRequest{System: system, Prompt: req.Text, Model: req.Model}, grammarSchema, req.Providers)
+	if err != nil {
+		return nil, err
+	}
+
+	var output grammarOutput
+	if err := decodeStructured(response.Data, &output); err != nil {
+		return nil, err
+	}
+
+	edits := make([]models.GrammarEdit, 0, len(output.Edits))
+	for _, e := range output.Edits {
+		edits = append(edits, models.GrammarEdit{
+			Original:    e.Original,
+			Replacement: e.Replacement,
+			Category:    e.Category,
+			Explanation: e.Explanation,
+		})
+	}
+	edits = localizarEdicoes(req.Text, edits)
+
+	pontos := writingPontosSemErros
+	if len(edits) > 0 {
+		pontos = writingPontosComErros
+	}
+
+	writing, err := repositories.IncrementarWriting(usuarioID, pontos)
+	if err != nil {
+		return nil, errors.New("erro ao atualizar progresso do usuário")
+	}
+
+	return &models.GrammarCheckResponse{
+		Original:      req.Text,
+		Corrected:     output.Corrected,
+		Edits:         edits,
+		WritingPoints: pontos,
+		Writing:       writing,
+		Provider:      response.Provider,
+		Model:         response.Model,
+	}, nil
+}
+
+// localizarEdicoes preenche Start/End (em caracteres) procurando cada trecho original no texto,
+// primeiro a partir do fim da edição anterior e depois desde o início. Só vale uma ocorrência
+// que não se sobreponha a outra edição; trechos sem ocorrência livre são descartados, de modo
+// que as edições devolvidas nunca se sobrepõem.
+func localizarEdicoes(text string, edits []models.GrammarEdit) []models.GrammarEdit {
+	type trecho struct{ start, end int } // em bytes
+	var ocupados []trecho
+
+	livre := func(start, end int) bool {
+		for _, o := range ocupados {
+			if start < o.end && o.start < end {
+				return false
+			}
+		}
+		return true
+	}
+
+	// ocorrencia procura a primeira ocorrência livre a partir de `from`
+	ocorrencia := func(original string, from int) int {
+		for from <= len(text) {
+			i := strings.Index(text[from:], original)
+			if i < 0 {
+				return -1
+			}
+			i += from
+			if livre(i, i+len(original)) {
+				return i
+			}
+			from = i + 1
+		}
+		return -1
+	}
+
+	located := make([]models.GrammarEdit, 0, len(edits))
+	cursor := 0
+	for _, e := range edits {
+		i := ocorrencia(e.Original, cursor)
+		if i < 0 {
+			i = ocorrencia(e.Original, 0)
+		}
+		if i < 0 {
+			log.Printf("⚠️ Correção descartada: trecho %q não encontrado no texto (ou sobreposto a outra correção)", e.Original)
+			continue
+		}
+
+		end := i + len(e.Original)
+		e.Start = utf8.RuneCountInString(text[:i])
+		e.End = e.Start + utf8.RuneCountInString(e.Original)
+		located = append(located, e)
+		ocupados = append(ocupados, trecho{i, end})
+		cursor = max(cursor, end)
+	}
+
+	sort.SliceStable(located, func(a, b int) bool { return located[a].Start < located[b].Start })
+	return located
+}
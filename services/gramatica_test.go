@@ -0,0 +1,47 @@
+package services
+
+import (
+	"lingobotAPI-GO/models"
+	"testing"
+)
+
+func TestLocalizarEdicoes(t *testing.T) {
+	text := "She go to school and she go home"
+
+	edits := localizarEdicoes(text, []models.GrammarEdit{
+		{Original: "go", Replacement: "goes"},
+		{Original: "go", Replacement: "goes"},
+		{Original: "She go", Replacement: "She goes"}, // sobrepõe a primeira edição
+		{Original: "went", Replacement: "goes"},       // não existe no texto
+	})
+
+	if len(edits) != 2 {
+		t.Fatalf("len(edits) = %d, want 2: %+v", len(edits), edits)
+	}
+
+	runes := []rune(text)
+	for i, e := range edits {
+		if got := string(runes[e.Start:e.End]); got != e.Original {
+			t.Errorf("edits[%d]: text[%d:%d] = %q, want %q", i, e.Start, e.End, got, e.Original)
+		}
+		if i > 0 && e.Start < edits[i-1].End {
+			t.Errorf("edits[%d] (%d-%d) overlaps edits[%d] (%d-%d)", i, e.Start, e.End, i-1, edits[i-1].Start, edits[i-1].End)
+		}
+	}
+	if edits[0].Start != 4 || edits[1].Start != 25 {
+		t.Errorf("starts = %d, %d, want 4, 25", edits[0].Start, edits[1].Start)
+	}
+}
+
+func TestLocalizarEdicoesOffsetsEmCaracteres(t *testing.T) {
+	text := "Ele está cansado e ela estão feliz"
+
+	edits := localizarEdicoes(text, []models.GrammarEdit{{Original: "estão", Replacement: "está"}})
+
+	if len(edits) != 1 {
+		t.Fatalf("len(edits) = %d, want 1", len(edits))
+	}
+	if got := string([]rune(text)[edits[0].Start:edits[0].End]); got != "estão" {
+		t.Errorf("text[%d:%d] = %q, want %q", edits[0].Start, edits[0].End, got, "estão")
+	}
+}
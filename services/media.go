@@ -1,8 +1,9 @@
 package services
 
 import (
-	"LingobotAPI-GO/utils"
+	"lingobotAPI-GO/utils"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -22,14 +23,35 @@ var VoiceIDs = []string{
 	"EXAVITQu4vr4xnSDxMaL", // 5 - Bella (feminina padrão)
 }
 
+// elevenLabsErrorResponse é o corpo de erro do ElevenLabs ({"detail": {"status", "message"}})
+type elevenLabsErrorResponse struct {
+	Detail struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"detail"`
+}
+
+type assemblyAIUploadResponse struct {
+	UploadURL string `json:"upload_url"`
+}
+
+// assemblyAITranscript é a resposta da criação e do polling da transcrição.
+// Nos erros de status HTTP só o campo Error vem preenchido.
+type assemblyAITranscript struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Text   string `json:"text"`
+	Error  string `json:"error"`
+}
+
 // GenerateTTSGoogle gera áudio usando edge-tts (Google TTS)
-func GenerateTTSGoogle(text string) ([]byte, error) {
+func GenerateTTSGoogle(ctx context.Context, text string) ([]byte, error) {
 	// Cria arquivo temporário para o áudio
 	tempFile := filepath.Join(os.TempDir(), fmt.Sprintf("tts_%d.mp3", time.Now().UnixNano()))
 	defer os.Remove(tempFile)
 
 	// Executa edge-tts via comando
-	cmd := exec.Command("edge-tts", "--text", text, "--voice", "en-US-ChristopherNeural", "--write-media", tempFile)
+	cmd := exec.CommandContext(ctx, "edge-tts", "--text", text, "--voice", "en-US-ChristopherNeural", "--write-media", tempFile)
 	if err := cmd.Run(); err != nil {
 		return nil, fmt.Errorf("erro ao executar edge-tts: %v", err)
 	}
@@ -44,13 +66,15 @@ func GenerateTTSGoogle(text string) ([]byte, error) {
 }
 
 // GenerateTTSElevenLabs gera áudio usando ElevenLabs
-func GenerateTTSElevenLabs(text, voiceID string) ([]byte, error) {
-	apiKey := os.Getenv("ELEVENLABS_KEY1")
-	if apiKey == "" {
-		return nil, errors.New("ElevenLabs API key not configured")
-	}
+func GenerateTTSElevenLabs(ctx context.Context, text, voiceID string) ([]byte, error) {
+	return withAPIKey(ctx, "elevenlabs", func(ctx context.Context, apiKey string) ([]byte, error) {
+		return generateTTSElevenLabs(ctx, text, voiceID, apiKey)
+	})
+}
 
-	url := fmt.Sprintf("https://api.elevenlabs.io/v1/text-to-speech/%s", voiceID)
+// generateTTSElevenLabs gera o áudio com uma chave do pool (withAPIKey)
+func generateTTSElevenLabs(ctx context.Context, text, voiceID, apiKey string) ([]byte, error) {
+	url := providerURL("elevenlabs", fmt.Sprintf("/text-to-speech/%s", voiceID))
 
 	payload := map[string]interface{}{
 		"text":     text,
@@ -64,20 +88,28 @@ func GenerateTTSElevenLabs(text, voiceID string) ([]byte, error) {
 	}
 
 	jsonData, _ := utils.Marshal(payload)
-	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	req.Header.Set("xi-api-key", apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "audio/mpeg")
 
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := doWithRetry(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("xi-api-key", apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "audio/mpeg")
+		return req, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ElevenLabs API returned status %d", resp.StatusCode)
+		var errResp elevenLabsErrorResponse
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		utils.Unmarshal(body, &errResp)
+		return nil, &ProviderStatusError{Provider: "ElevenLabs", StatusCode: resp.StatusCode, Message: errResp.Detail.Message}
 	}
 
 	audioData, err := io.ReadAll(resp.Body)
@@ -89,7 +121,7 @@ func GenerateTTSElevenLabs(text, voiceID string) ([]byte, error) {
 }
 
 // GenerateTTS gera áudio com fallback (ElevenLabs -> Google TTS)
-func GenerateTTS(text string, voiceIndex int, premium bool) ([]byte, error) {
+func GenerateTTS(ctx context.Context, text string, voiceIndex int, premium bool) ([]byte, error) {
 	// Valida índice de voz
 	if voiceIndex < 0 || voiceIndex >= len(VoiceIDs) {
 		voiceIndex = len(VoiceIDs) - 1 // Padrão: última voz
@@ -99,7 +131,7 @@ func GenerateTTS(text string, voiceIndex int, premium bool) ([]byte, error) {
 
 	// Se premium, tenta ElevenLabs primeiro
 	if premium {
-		audioData, err := GenerateTTSElevenLabs(text, voiceID)
+		audioData, err := GenerateTTSElevenLabs(ctx, text, voiceID)
 		if err == nil {
 			return audioData, nil
 		}
@@ -107,46 +139,56 @@ func GenerateTTS(text string, voiceIndex int, premium bool) ([]byte, error) {
 	}
 
 	// Fallback: Google TTS
-	return GenerateTTSGoogle(text)
+	return GenerateTTSGoogle(ctx, text)
 }
 
-// TranscribeAudio transcreve áudio usando AssemblyAI
-func TranscribeAudio(filePath string) (string, error) {
-	apiKey := os.Getenv("ASSEMBLYAI_KEY")
-	if apiKey == "" {
-		return "", errors.New("AssemblyAI API key not configured")
-	}
+// TranscribeAudio transcreve áudio usando AssemblyAI.
+// O polling termina quando a transcrição conclui ou quando ctx é cancelado.
+func TranscribeAudio(ctx context.Context, filePath string) (string, error) {
+	return withAPIKey(ctx, "assemblyai", func(ctx context.Context, apiKey string) (string, error) {
+		return transcribeAudio(ctx, filePath, apiKey)
+	})
+}
 
+// transcribeAudio faz upload, pedido e polling com uma chave do pool (withAPIKey)
+func transcribeAudio(ctx context.Context, filePath, apiKey string) (string, error) {
 	// 1. Upload do arquivo
-	uploadURL := "https://api.assemblyai.com/v2/upload"
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	req, _ := http.NewRequest("POST", uploadURL, file)
-	req.Header.Set("authorization", apiKey)
+	uploadURL := providerURL("assemblyai", "/upload")
 
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := doWithRetry(ctx, client, func() (*http.Request, error) {
+		// O arquivo é reaberto a cada tentativa (o client fecha o corpo da requisição)
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		req.Header.Set("authorization", apiKey)
+		return req, nil
+	})
 	if err != nil {
 		return "", err
 	}
 
-	var uploadResp map[string]interface{}
+	var uploadResp assemblyAIUploadResponse
 	body, _ := io.ReadAll(resp.Body)
 	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &ProviderStatusError{Provider: "AssemblyAI", StatusCode: resp.StatusCode, Message: assemblyAIErrorMessage(body)}
+	}
 	utils.Unmarshal(body, &uploadResp)
 
-	audioURL, ok := uploadResp["upload_url"].(string)
-	if !ok {
+	audioURL := uploadResp.UploadURL
+	if audioURL == "" {
 		return "", errors.New("failed to get upload URL")
 	}
 
 	// 2. Solicita transcrição
-	transcriptURL := "https://api.assemblyai.com/v2/transcript"
+	transcriptURL := providerURL("assemblyai", "/transcript")
 
 	transcriptPayload := map[string]interface{}{
 		"audio_url":    audioURL,
@@ -154,56 +196,80 @@ func TranscribeAudio(filePath string) (string, error) {
 	}
 
 	jsonData, _ := utils.Marshal(transcriptPayload)
-	req, _ = http.NewRequest("POST", transcriptURL, bytes.NewBuffer(jsonData))
-	req.Header.Set("authorization", apiKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err = client.Do(req)
+	resp, err = doWithRetry(ctx, client, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", transcriptURL, bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("authorization", apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
 	if err != nil {
 		return "", err
 	}
 
-	var transcriptResp map[string]interface{}
+	var transcriptResp assemblyAITranscript
 	body, _ = io.ReadAll(resp.Body)
 	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &ProviderStatusError{Provider: "AssemblyAI", StatusCode: resp.StatusCode, Message: assemblyAIErrorMessage(body)}
+	}
 	utils.Unmarshal(body, &transcriptResp)
 
-	transcriptID, ok := transcriptResp["id"].(string)
-	if !ok {
+	transcriptID := transcriptResp.ID
+	if transcriptID == "" {
 		return "", errors.New("failed to get transcript ID")
 	}
 
 	// 3. Polling: aguarda conclusão da transcrição
-	pollURL := fmt.Sprintf("https://api.assemblyai.com/v2/transcript/%s", transcriptID)
+	pollURL := providerURL("assemblyai", fmt.Sprintf("/transcript/%s", transcriptID))
 
 	for {
-		time.Sleep(2 * time.Second)
-
-		req, _ = http.NewRequest("GET", pollURL, nil)
-		req.Header.Set("authorization", apiKey)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
 
-		resp, err = client.Do(req)
+		resp, err = doWithRetry(ctx, client, func() (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, "GET", pollURL, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("authorization", apiKey)
+			return req, nil
+		})
 		if err != nil {
 			return "", err
 		}
 
-		var pollResp map[string]interface{}
+		var pollResp assemblyAITranscript
 		body, _ = io.ReadAll(resp.Body)
 		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", &ProviderStatusError{Provider: "AssemblyAI", StatusCode: resp.StatusCode, Message: assemblyAIErrorMessage(body)}
+		}
 		utils.Unmarshal(body, &pollResp)
 
-		status, _ := pollResp["status"].(string)
-
-		if status == "completed" {
-			text, _ := pollResp["text"].(string)
-			return text, nil
+		if pollResp.Status == "completed" {
+			return pollResp.Text, nil
 		}
 
-		if status == "error" {
-			errorMsg, _ := pollResp["error"].(string)
-			return "", fmt.Errorf("transcription error: %s", errorMsg)
+		if pollResp.Status == "error" {
+			return "", fmt.Errorf("transcription error: %s", pollResp.Error)
 		}
 
 		// Continua polling se status for "queued" ou "processing"
 	}
-}
\ No newline at end of file
+}
+
+// assemblyAIErrorMessage lê a mensagem do corpo de erro do AssemblyAI ({"error": ...})
+func assemblyAIErrorMessage(body []byte) string {
+	var result assemblyAITranscript
+	if err := utils.Unmarshal(body, &result); err != nil {
+		return ""
+	}
+	return result.Error
+}
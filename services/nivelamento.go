@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Erros do teste de nivelamento
+var (
+	ErrNivelamentoNaoEncontrado = errors.New("teste de nivelamento não encontrado")
+	ErrNivelamentoConcluido     = errors.New("teste de nivelamento já concluído")
+	ErrQuestaoNaoEncontrada     = errors.New("questão não encontrada neste teste")
+	ErrQuestaoJaRespondida      = errors.New("questão já respondida")
+	ErrRespostaInvalida         = errors.New("resposta inválida: informe o índice (0-3) ou o texto de uma das opções")
+)
+
+const (
+	nivelamentoTotalQuestoes = 10
+	nivelamentoNivelInicial  = "B1"
+	// A cada nivelamentoIntervaloEscrita questões, uma é de escrita livre corrigida pela IA
+	nivelamentoIntervaloEscrita = 4
+)
+
+// niveisCEFR em ordem crescente; o teste sobe um nível a cada acerto e desce a cada erro
+var niveisCEFR = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// cefrDifficulty traduz o nível CEFR para a dificuldade usada nas personas
+var cefrDifficulty = map[string]string{
+	"A1": "easy",
+	"A2": "easy",
+	"B1": "medium",
+	"B2": "medium",
+	"C1": "hard",
+	"C2": "hard",
+}
+
+// Schemas pedidos à IA para gerar e corrigir as questões
+var (
+	questaoMultiplaEscolhaSchema = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"question", "options", "answer_index", "explanation"},
+		"properties": map[string]interface{}{
+			"question":     map[string]interface{}{"type": "string", "minLength": 1},
+			"options":      map[string]interface{}{"type": "array", "minItems": 4, "maxItems": 4, "items": map[string]interface{}{"type": "string", "minLength": 1}},
+			"answer_index": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 3},
+			"explanation":  map[string]interface{}{"type": "string", "minLength": 1},
+		},
+	}
+	questaoEscritaSchema = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"task"},
+		"properties": map[string]interface{}{
+			"task": map[string]interface{}{"type": "string", "minLength": 1},
+		},
+	}
+	correcaoEscritaSchema = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"correct", "feedback"},
+		"properties": map[string]interface{}{
+			"correct":  map[string]interface{}{"type": "boolean"},
+			"feedback": map[string]interface{}{"type": "string", "minLength": 1},
+		},
+	}
+)
+
+// IniciarNivelamento abre um teste novo no nível inicial (o teste em andamento é abandonado)
+func IniciarNivelamento(usuarioID int) (*models.Nivelamento, error) {
+	n := &models.Nivelamento{
+		UsuarioID:     usuarioID,
+		Status:        models.NivelamentoEmAndamento,
+		NivelAtual:    nivelamentoNivelInicial,
+		TotalQuestoes: nivelamentoTotalQuestoes,
+	}
+
+	if err := repositories.InsertNivelamento(n); err != nil {
+		return nil, errors.New("erro ao iniciar teste de nivelamento")
+	}
+
+	return n, nil
+}
+
+// GetNivelamento retorna o teste e as questões já geradas
+func GetNivelamento(usuarioID, nivelamentoID int) (*models.NivelamentoDetalhe, error) {
+	n, err := buscarNivelamento(usuarioID, nivelamentoID)
+	if err != nil {
+		return nil, err
+	}
+
+	questoes, err := repositories.GetQuestoesNivelamento(nivelamentoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar questões")
+	}
+
+	return &models.NivelamentoDetalhe{Nivelamento: *n, Questoes: questoes}, nil
+}
+
+// ProximaQuestaoNivelamento retorna a questão pendente ou gera uma nova no nível atual do teste.
+// O teste é cobrado uma vez, ao iniciar; aqui o consumo de tokens é apenas registrado.
+func ProximaQuestaoNivelamento(ctx context.Context, usuarioID, nivelamentoID int) (*models.NivelamentoQuestao, error) {
+	n, err := buscarNivelamento(usuarioID, nivelamentoID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != models.NivelamentoEmAndamento {
+		return nil, ErrNivelamentoConcluido
+	}
+
+	questoes, err := repositories.GetQuestoesNivelamento(nivelamentoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar questões")
+	}
+
+	if len(questoes) > 0 && questoes[len(questoes)-1].RespondidaEm == nil {
+		return &questoes[len(questoes)-1], nil
+	}
+
+	progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar progresso do usuário")
+	}
+
+	ordem := len(questoes) + 1
+	anteriores := make([]string, 0, len(questoes))
+	for _, q := range questoes {
+		anteriores = append(anteriores, q.Enunciado)
+	}
+
+	q, err := gerarQuestaoNivelamento(ContextWithUsuarioIA(ctx, usuarioID), languageName(progresso.Learning), n.NivelAtual, ordem, anteriores)
+	if err != nil {
+		return nil, err
+	}
+	q.NivelamentoID = nivelamentoID
+
+	inserida, err := repositories.InsertQuestaoNivelamento(q)
+	if err != nil {
+		return nil, errors.New("erro ao salvar questão")
+	}
+	if !inserida {
+		// Outra requisição gerou esta questão ao mesmo tempo: devolve a que ficou gravada
+		return questaoNivelamento(nivelamentoID, ordem)
+	}
+
+	return q, nil
+}
+
+// questaoNivelamento busca a questão do teste pela ordem
+func questaoNivelamento(nivelamentoID, ordem int) (*models.NivelamentoQuestao, error) {
+	questoes, err := repositories.GetQuestoesNivelamento(nivelamentoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar questões")
+	}
+	for i := range questoes {
+		if questoes[i].Ordem == ordem {
+			return &questoes[i], nil
+		}
+	}
+	return nil, ErrQuestaoNaoEncontrada
+}
+
+// ResponderNivelamento corrige a resposta (multiple-choice pelo gabarito, escrita pela IA), ajusta o
+// nível da próxima questão e, na última resposta, grava o nível CEFR e a dificuldade do usuário
+func ResponderNivelamento(ctx context.Context, usuarioID, nivelamentoID int, req models.ResponderNivelamentoRequest) (*models.NivelamentoResultado, error) {
+	n, err := buscarNivelamento(usuarioID, nivelamentoID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != models.NivelamentoEmAndamento {
+		return nil, ErrNivelamentoConcluido
+	}
+
+	questoes, err := repositories.GetQuestoesNivelamento(nivelamentoID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar questões")
+	}
+
+	i := slices.IndexFunc(questoes, func(q models.NivelamentoQuestao) bool { return q.ID == req.QuestaoID })
+	if i < 0 {
+		return nil, ErrQuestaoNaoEncontrada
+	}
+	q := &questoes[i]
+	if q.RespondidaEm != nil {
+		return nil, ErrQuestaoJaRespondida
+	}
+
+	resultado := &models.NivelamentoResultado{}
+	switch q.Tipo {
+	case models.QuestaoMultiplaEscolha:
+		escolha, err := opcaoEscolhida(q.Opcoes, req.Resposta)
+		if err != nil {
+			return nil, err
+		}
+		resultado.Correta = q.RespostaCorreta != nil && escolha == *q.RespostaCorreta
+		resultado.RespostaCorreta = q.RespostaCorreta
+		if q.Explicacao != nil {
+			resultado.Feedback = *q.Explicacao
+		}
+	default:
+		progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+		if err != nil {
+			return nil, errors.New("erro ao buscar progresso do usuário")
+		}
+		resultado.Correta, resultado.Feedback, err = corrigirEscritaNivelamento(ContextWithUsuarioIA(ctx, usuarioID), languageName(progresso.Learning), q, req.Resposta)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q.Resposta = &req.Resposta
+	q.Correta = &resultado.Correta
+	q.Feedback = &resultado.Feedback
+
+	nivel := proximoNivelCEFR(q.Cefr, resultado.Correta)
+	respondida, err := repositories.ResponderQuestaoNivelamento(q, nivel)
+	if err != nil {
+		return nil, errors.New("erro ao salvar resposta")
+	}
+	if !respondida {
+		return nil, ErrQuestaoJaRespondida
+	}
+
+	n.NivelAtual = nivel
+	n.Respondidas++
+
+	if n.Respondidas >= n.TotalQuestoes {
+		cefr := resultadoCEFR(questoes)
+		n.Cefr = &cefr
+		resultado.Difficulty = cefrDifficulty[cefr]
+		if err := repositories.ConcluirNivelamento(n, resultado.Difficulty); err != nil {
+			return nil, errors.New("erro ao concluir teste de nivelamento")
+		}
+	}
+
+	resultado.Nivelamento = *n
+	return resultado, nil
+}
+
+// buscarNivelamento retorna o teste do usuário ou ErrNivelamentoNaoEncontrado
+func buscarNivelamento(usuarioID, nivelamentoID int) (*models.Nivelamento, error) {
+	n, err := repositories.GetNivelamento(usuarioID, nivelamentoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNivelamentoNaoEncontrado
+	}
+	if err != nil {
+		return nil, errors.New("erro ao buscar teste de nivelamento")
+	}
+	return n, nil
+}
+
+// gerarQuestaoNivelamento pede à IA uma questão no nível CEFR informado. A cada
+// nivelamentoIntervaloEscrita questões a tarefa é de escrita livre; as demais são multiple-choice.
+func gerarQuestaoNivelamento(ctx context.Context, language, cefr string, ordem int, anteriores []string) (*models.NivelamentoQuestao, error) {
+	system := fmt.Sprintf(`You are Lingobot, writing a %s placement test. Write exactly one question at CEFR level %s.
+The question tests grammar, vocabulary or reading at that level and must not depend on earlier questions.`, language, cefr)
+	if len(anteriores) > 0 {
+		system += "\nDo not repeat these questions:\n- " + strings.Join(anteriores, "\n- ")
+	}
+
+	q := &models.NivelamentoQuestao{Ordem: ordem, Cefr: cefr}
+
+	if ordem%nivelamentoIntervaloEscrita == 0 {
+		response, err := CallAIStructured(ctx, models.CompletionRequest{
+			System: system,
+			Prompt: fmt.Sprintf("Write a short writing task (one to three sentences to write) in %s for a %s student.", language, cefr),
+		}, questaoEscritaSchema, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var output struct {
+			Task string `json:"task"`
+		}
+		if err := decodeStructured(response.Data, &output); err != nil {
+			return nil, err
+		}
+
+		q.Tipo = models.QuestaoEscrita
+		q.Enunciado = output.Task
+		return q, nil
+	}
+
+	response, err := CallAIStructured(ctx, models.CompletionRequest{
+		System: system,
+		Prompt: fmt.Sprintf("Write a multiple-choice question in %s with exactly four options and one correct answer for a %s student.", language, cefr),
+	}, questaoMultiplaEscolhaSchema, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var output struct {
+		Question    string   `json:"question"`
+		Options     []string `json:"options"`
+		AnswerIndex int      `json:"answer_index"`
+		Explanation string   `json:"explanation"`
+	}
+	if err := decodeStructured(response.Data, &output); err != nil {
+		return nil, err
+	}
+
+	q.Tipo = models.QuestaoMultiplaEscolha
+	q.Enunciado = output.Question
+	q.Opcoes = output.Options
+	q.RespostaCorreta = &output.AnswerIndex
+	q.Explicacao = &output.Explanation
+	return q, nil
+}
+
+// corrigirEscritaNivelamento pede à IA para avaliar se o texto do aluno cumpre a tarefa no nível da questão
+func corrigirEscritaNivelamento(ctx context.Context, language string, q *models.NivelamentoQuestao, resposta string) (bool, string, error) {
+	system := fmt.Sprintf(`You are Lingobot, grading a %[1]s placement test. The task below is at CEFR level %[2]s.
+Mark the answer as correct only if it completes the task in understandable %[1]s with the accuracy expected at %[2]s.
+Feedback is one or two short sentences for the student.
+
+Task: %[3]s`, language, q.Cefr, q.Enunciado)
+
+	response, err := CallAIStructured(ctx, models.CompletionRequest{System: system, Prompt: resposta}, correcaoEscritaSchema, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	var output struct {
+		Correct  bool   `json:"correct"`
+		Feedback string `json:"feedback"`
+	}
+	if err := decodeStructured(response.Data, &output); err != nil {
+		return false, "", err
+	}
+
+	return output.Correct, output.Feedback, nil
+}
+
+// opcaoEscolhida aceita o índice da opção ou o texto dela (sem diferenciar maiúsculas)
+func opcaoEscolhida(opcoes []string, resposta string) (int, error) {
+	resposta = strings.TrimSpace(resposta)
+	if i, err := strconv.Atoi(resposta); err == nil && i >= 0 && i < len(opcoes) {
+		return i, nil
+	}
+	for i, opcao := range opcoes {
+		if strings.EqualFold(strings.TrimSpace(opcao), resposta) {
+			return i, nil
+		}
+	}
+	return 0, ErrRespostaInvalida
+}
+
+// proximoNivelCEFR sobe um nível após um acerto e desce após um erro, sem sair de A1–C2
+func proximoNivelCEFR(cefr string, correta bool) string {
+	i := slices.Index(niveisCEFR, cefr)
+	if i < 0 {
+		return nivelamentoNivelInicial
+	}
+	if correta {
+		i++
+	} else {
+		i--
+	}
+	return niveisCEFR[min(max(i, 0), len(niveisCEFR)-1)]
+}
+
+// resultadoCEFR é o maior nível em que o aluno acertou pelo menos metade das questões
+// (com ao menos um acerto); sem acertos o resultado é A1
+func resultadoCEFR(questoes []models.NivelamentoQuestao) string {
+	acertos := make(map[string]int)
+	erros := make(map[string]int)
+	for _, q := range questoes {
+		if q.Correta == nil {
+			continue
+		}
+		if *q.Correta {
+			acertos[q.Cefr]++
+		} else {
+			erros[q.Cefr]++
+		}
+	}
+
+	for i := len(niveisCEFR) - 1; i >= 0; i-- {
+		nivel := niveisCEFR[i]
+		if acertos[nivel] > 0 && acertos[nivel] >= erros[nivel] {
+			return nivel
+		}
+	}
+	return niveisCEFR[0]
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ErrPersonaNotFound é retornado quando a persona pedida não existe
+var ErrPersonaNotFound = errors.New("persona not found")
+
+// persona é um template de system prompt do tutor
+type persona struct {
+	descricao string
+	tmpl      *template.Template
+}
+
+// personaData são os dados do usuário disponíveis nos templates
+type personaData struct {
+	Language     string // nome do idioma em inglês (ex.: "English")
+	LanguageCode string // código salvo em usuario_progresso.learning (ex.: "en")
+	Difficulty   string // easy, medium ou hard
+	Level        int
+}
+
+// languageNames traduz o código salvo em Learning para o nome usado nos prompts
+var languageNames = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"ja": "Japanese",
+	"zh": "Chinese",
+	"ko": "Korean",
+}
+
+// personas é o catálogo de templates, indexado pelo nome usado em `persona` nas requisições
+var personas = map[string]persona{
+	"grammar-corrector": newPersona(
+		"Corrige a gramática do texto do aluno e explica os erros",
+		`You are Lingobot, a friendly {{.Language}} teacher.
+The student is learning {{.Language}} at {{.Difficulty}} difficulty (level {{.Level}}).
+Correct the grammar, spelling and word choice of every message the student sends.
+Reply with the corrected text first, then a short list of the mistakes with a one-line explanation each.
+Keep explanations simple enough for a {{.Difficulty}} learner. If the text is already correct, say so and praise the student.`,
+	),
+	"conversation-partner": newPersona(
+		"Conversa livre no idioma estudado, no nível do aluno",
+		`You are Lingobot, a friendly conversation partner who only speaks {{.Language}}.
+The student is practising {{.Language}} at {{.Difficulty}} difficulty (level {{.Level}}).
+Keep the conversation going with natural replies and one follow-up question per message.
+{{- if eq .Difficulty "easy"}}
+Use short sentences and common everyday vocabulary.
+{{- else if eq .Difficulty "hard"}}
+Use rich vocabulary and idiomatic expressions.
+{{- end}}
+Do not correct mistakes unless they make the message impossible to understand.`,
+	),
+	"vocab-quiz": newPersona(
+		"Quiz de vocabulário com uma pergunta por vez",
+		`You are Lingobot, a {{.Language}} vocabulary quiz master.
+The student is learning {{.Language}} at {{.Difficulty}} difficulty (level {{.Level}}).
+Ask one vocabulary question at a time, suited to that level, and wait for the answer.
+When the student answers, say whether it is right, give the correct answer with an example sentence, then ask the next question.`,
+	),
+}
+
+// newPersona compila o template da persona (erros de template são bugs, por isso o panic)
+func newPersona(descricao, text string) persona {
+	return persona{
+		descricao: descricao,
+		tmpl:      template.Must(template.New("").Parse(text)),
+	}
+}
+
+// ListPersonas retorna as personas disponíveis (nome → descrição)
+func ListPersonas() []models.PersonaInfo {
+	names := make([]string, 0, len(personas))
+	for name := range personas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]models.PersonaInfo, 0, len(names))
+	for _, name := range names {
+		result = append(result, models.PersonaInfo{Name: name, Descricao: personas[name].descricao})
+	}
+	return result
+}
+
+// BuildSystemPrompt renderiza a persona com o idioma e a dificuldade do usuário.
+// Persona vazia não gera system prompt.
+func BuildSystemPrompt(usuarioID int, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	p, ok := personas[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrPersonaNotFound, name)
+	}
+
+	progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+	if err != nil {
+		return "", errors.New("erro ao buscar progresso do usuário")
+	}
+
+	data := personaData{
+		Language:     languageName(progresso.Learning),
+		LanguageCode: progresso.Learning,
+		Difficulty:   progresso.Difficulty,
+		Level:        progresso.Level,
+	}
+	if data.Difficulty == "" {
+		data.Difficulty = "medium"
+	}
+
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("erro ao renderizar persona %s: %v", name, err)
+	}
+
+	return sb.String(), nil
+}
+
+// languageName retorna o nome do idioma a partir do código (ou o próprio código se desconhecido)
+func languageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	if code == "" {
+		return languageNames["en"]
+	}
+	return code
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"lingobotAPI-GO/config"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryableStatus são os status que valem nova tentativa: limite de requisições e
+// instabilidade do fornecedor. Os demais 4xx são erros do pedido e falham na hora.
+var retryableStatus = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// doWithRetry executa a requisição seguindo a política de retry (config.GetAIRetryConfig).
+//
+// newRequest é chamado a cada tentativa, pois o corpo de uma requisição só pode ser lido
+// uma vez. Erros de rede e os status de retryableStatus são repetidos com backoff
+// exponencial e jitter, ou esperando o Retry-After quando o fornecedor informa; o 429 só é
+// repetido quando withAPIKey não tem outra chave para tentar. O contexto
+// limita tudo: se a próxima espera passar do deadline, a última resposta é devolvida.
+// Quem chama é responsável por fechar o corpo da resposta retornada.
+func doWithRetry(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	policy := config.GetAIRetryConfig()
+
+	for attempt := 1; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if !shouldRetry(ctx, resp, err) || attempt >= policy.MaxAttempts {
+			return resp, err
+		}
+
+		delay := retryDelay(policy, attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// Não dá tempo de esperar: devolve a falha atual em vez de estourar o deadline
+			return resp, err
+		}
+
+		reason := "erro de rede"
+		if err == nil {
+			reason = resp.Status
+			// Descarta o corpo da tentativa que falhou para liberar a conexão
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		log.Printf("⚠️  %s: tentativa %d/%d falhou (%s), nova tentativa em %v", req.URL.Host, attempt, policy.MaxAttempts, reason, delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// shouldRetry decide se a tentativa pode ser repetida
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// Cancelamento e deadline do próprio contexto não são falhas do fornecedor
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests && keyRotation(ctx) {
+		// A cota é da chave: withAPIKey suspende esta e tenta a próxima do pool
+		return false
+	}
+	return retryableStatus[resp.StatusCode]
+}
+
+// retryDelay calcula a espera antes da próxima tentativa.
+// Com Retry-After vale o tempo pedido pelo fornecedor (limitado a MaxDelay);
+// sem ele, BaseDelay * 2^(tentativa-1) com "equal jitter" (entre metade e o total).
+func retryDelay(policy config.AIRetryConfig, attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(delay, policy.MaxDelay)
+		}
+	}
+
+	backoff := policy.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > policy.MaxDelay {
+		backoff = policy.MaxDelay
+	}
+
+	half := backoff / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter aceita os dois formatos do header: segundos ou data HTTP
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
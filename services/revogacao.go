@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"lingobotAPI-GO/repositories"
+	"lingobotAPI-GO/utils"
+	"log"
+	"sync"
+	"time"
+)
+
+// Erros do logout
+var (
+	ErrRevogacaoFutura = errors.New("antes não pode estar no futuro")
+	ErrTokenSemJTI     = errors.New("token sem jti não pode ser revogado")
+)
+
+const (
+	// Por quanto tempo uma consulta ao banco vale no cache. Revogações feitas nesta instância
+	// valem na hora; as feitas em outras instâncias chegam em até revogacaoCacheTTL.
+	revogacaoCacheTTL = 30 * time.Second
+	// Acima disso as entradas vencidas são descartadas (e o cache zerado se ainda estiver cheio)
+	revogacaoCacheMax = 10000
+)
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // opcional: encerra também a família do refresh token
+}
+
+type LogoutTodosRequest struct {
+	Antes *time.Time `json:"antes"` // opcional: revoga os tokens emitidos até esse instante (padrão: agora)
+}
+
+type LogoutTodosResponse struct {
+	Mensagem      string    `json:"mensagem"`
+	RevogadoAntes time.Time `json:"revogado_antes"`
+}
+
+type jtiRevogadoCache struct {
+	revogado bool
+	expira   time.Time
+}
+
+type corteRevogacaoCache struct {
+	antes  *time.Time
+	expira time.Time
+}
+
+// revogacaoStore guarda em memória o resultado das consultas a token_revogado e usuario_revogacao
+type revogacaoStore struct {
+	mu     sync.Mutex
+	jtis   map[string]jtiRevogadoCache
+	cortes map[int]corteRevogacaoCache
+}
+
+var revogacao = &revogacaoStore{
+	jtis:   map[string]jtiRevogadoCache{},
+	cortes: map[int]corteRevogacaoCache{},
+}
+
+// TokenRevogado informa se o token foi revogado pelo JTI (logout) ou por ter sido emitido
+// até o corte do usuário (logout em todos os dispositivos)
+func TokenRevogado(claims *utils.Claims) (bool, error) {
+	if claims.JTI != "" {
+		revogado, err := revogacao.jtiRevogado(claims.JTI)
+		if err != nil || revogado {
+			return revogado, err
+		}
+	}
+
+	antes, err := revogacao.corte(claims.Sub)
+	if err != nil || antes == nil {
+		return false, err
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return !claims.IssuedAt.Time.After(*antes), nil
+}
+
+// Logout revoga o access token atual e, se informado, a família do refresh token do mesmo usuário
+func Logout(claims *utils.Claims, req LogoutRequest) error {
+	if claims.JTI == "" {
+		return ErrTokenSemJTI
+	}
+
+	expira := time.Now().Add(utils.AccessTokenTTL)
+	if claims.ExpiresAt != nil {
+		expira = claims.ExpiresAt.Time
+	}
+
+	if err := repositories.InsertTokenRevogado(claims.JTI, claims.Sub, expira.UTC()); err != nil {
+		log.Printf("❌ Erro ao revogar token %s: %v", claims.JTI, err)
+		return errors.New("erro ao encerrar sessão")
+	}
+	revogacao.marcarJTI(claims.JTI)
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	refresh, err := utils.ValidateToken(req.RefreshToken)
+	if err != nil || refresh.Type != "refresh" || refresh.Family == "" || refresh.Sub != claims.Sub {
+		return ErrRefreshTokenInvalido
+	}
+	if _, err := repositories.RevogarFamiliaRefreshToken(refresh.Family); err != nil {
+		log.Printf("❌ Erro ao revogar família de refresh token %s: %v", refresh.Family, err)
+		return errors.New("erro ao encerrar sessão")
+	}
+
+	return nil
+}
+
+// LogoutTodos revoga todos os tokens do usuário emitidos até `antes` (padrão: agora), inclusive
+// os refresh tokens. Tokens emitidos no mesmo segundo do corte também são revogados.
+func LogoutTodos(usuarioID int, req LogoutTodosRequest) (*LogoutTodosResponse, error) {
+	agora := time.Now()
+	antes := agora
+	if req.Antes != nil {
+		if req.Antes.After(agora) {
+			return nil, ErrRevogacaoFutura
+		}
+		antes = *req.Antes
+	}
+
+	// O iat do JWT tem precisão de segundos; as colunas TIMESTAMP guardam UTC
+	corte, err := repositories.RevogarTokensUsuario(usuarioID, antes.UTC().Truncate(time.Second))
+	if err != nil {
+		log.Printf("❌ Erro ao revogar tokens do usuário %d: %v", usuarioID, err)
+		return nil, errors.New("erro ao encerrar sessões")
+	}
+	revogacao.marcarCorte(usuarioID, corte)
+
+	return &LogoutTodosResponse{
+		Mensagem:      "Sessões encerradas em todos os dispositivos",
+		RevogadoAntes: corte,
+	}, nil
+}
+
+func (s *revogacaoStore) jtiRevogado(jti string) (bool, error) {
+	agora := time.Now()
+
+	s.mu.Lock()
+	entrada, ok := s.jtis[jti]
+	s.mu.Unlock()
+	if ok && (entrada.revogado || agora.Before(entrada.expira)) {
+		return entrada.revogado, nil
+	}
+
+	revogado, err := repositories.TokenRevogadoExiste(jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	if len(s.jtis) >= revogacaoCacheMax {
+		for k, e := range s.jtis {
+			if !e.revogado && agora.After(e.expira) {
+				delete(s.jtis, k)
+			}
+		}
+		if len(s.jtis) >= revogacaoCacheMax {
+			s.jtis = map[string]jtiRevogadoCache{}
+		}
+	}
+	s.jtis[jti] = jtiRevogadoCache{revogado: revogado, expira: agora.Add(revogacaoCacheTTL)}
+	s.mu.Unlock()
+
+	return revogado, nil
+}
+
+func (s *revogacaoStore) corte(usuarioID int) (*time.Time, error) {
+	agora := time.Now()
+
+	s.mu.Lock()
+	entrada, ok := s.cortes[usuarioID]
+	s.mu.Unlock()
+	if ok && agora.Before(entrada.expira) {
+		return entrada.antes, nil
+	}
+
+	antes, err := repositories.GetRevogadoAntes(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.cortes) >= revogacaoCacheMax {
+		for k, e := range s.cortes {
+			if agora.After(e.expira) {
+				delete(s.cortes, k)
+			}
+		}
+		if len(s.cortes) >= revogacaoCacheMax {
+			s.cortes = map[int]corteRevogacaoCache{}
+		}
+	}
+	s.cortes[usuarioID] = corteRevogacaoCache{antes: antes, expira: agora.Add(revogacaoCacheTTL)}
+	s.mu.Unlock()
+
+	return antes, nil
+}
+
+// marcarJTI registra a revogação feita nesta instância (um JTI revogado nunca volta a valer)
+func (s *revogacaoStore) marcarJTI(jti string) {
+	s.mu.Lock()
+	s.jtis[jti] = jtiRevogadoCache{revogado: true, expira: time.Now().Add(revogacaoCacheTTL)}
+	s.mu.Unlock()
+}
+
+// marcarCorte registra o novo corte do usuário feito nesta instância
+func (s *revogacaoStore) marcarCorte(usuarioID int, antes time.Time) {
+	s.mu.Lock()
+	s.cortes[usuarioID] = corteRevogacaoCache{antes: &antes, expira: time.Now().Add(revogacaoCacheTTL)}
+	s.mu.Unlock()
+}
@@ -5,6 +5,14 @@ import (
 	_ "lingobotAPI-GO/models"
 	"lingobotAPI-GO/repositories"
 	"lingobotAPI-GO/utils"
+	"strings"
+)
+
+// Erros do /update-user-data e do /usuarios/senha
+var (
+	ErrUsuarioDiferente = errors.New("id do corpo não corresponde ao usuário autenticado")
+	ErrSenhaIncorreta   = errors.New("senha atual incorreta")
+	ErrSenhaVazia       = errors.New("nova senha é obrigatória")
 )
 
 type UpdateUserDataRequest struct {
@@ -17,10 +25,7 @@ type UpdateUserDataRequest struct {
 	Level          *int        `json:"Level"`
 	Gender         *string     `json:"gender"`
 	DataNascimento *string     `json:"data_nascimento"`
-	Tokens         *int        `json:"tokens"`
 	Gemas          *int        `json:"gemas"`
-	Battery        *int        `json:"battery"`
-	Plano          *string     `json:"plano"`
 	Ranking        *int        `json:"ranking"`
 	Listening      *int        `json:"listening"`
 	Writing        *int        `json:"writing"`
@@ -38,16 +43,18 @@ type UpdateUserDataResponse struct {
 	AccessToken string `json:"access_token"`
 }
 
-// UpdateUserData atualiza os dados do usuário nas tabelas normalizadas e gera um novo JWT
-func UpdateUserData(req UpdateUserDataRequest) (*UpdateUserDataResponse, error) {
-	// Pega o ID do usuário (prioriza 'id', depois 'sub')
-	var userID int
-	if req.ID != nil {
-		userID = *req.ID
-	} else if req.Sub != nil {
-		userID = *req.Sub
-	} else {
-		return nil, errors.New("ID do usuário não fornecido")
+type AlterarSenhaRequest struct {
+	SenhaAtual string `json:"senha_atual" binding:"required"`
+	NovaSenha  string `json:"nova_senha" binding:"required"`
+}
+
+// UpdateUserData atualiza os dados do usuário autenticado nas tabelas normalizadas e gera um novo JWT.
+// O id/sub do corpo, se enviado, deve ser o do token. Trocar o email exige um token fresh
+// (RequireFreshToken).
+func UpdateUserData(claims *utils.Claims, req UpdateUserDataRequest) (*UpdateUserDataResponse, error) {
+	userID := claims.Sub
+	if (req.ID != nil && *req.ID != userID) || (req.Sub != nil && *req.Sub != userID) {
+		return nil, ErrUsuarioDiferente
 	}
 
 	// Busca o usuário completo no banco
@@ -56,17 +63,6 @@ func UpdateUserData(req UpdateUserDataRequest) (*UpdateUserDataResponse, error)
 		return nil, errors.New("usuário não encontrado")
 	}
 
-	// Validação de battery (0 a 10)
-	if req.Battery != nil {
-		battery := *req.Battery
-		if battery > 10 {
-			battery = 10
-		} else if battery < 0 {
-			battery = 0
-		}
-		req.Battery = &battery
-	}
-
 	// Atualiza campos da tabela usuario
 	if req.Nome != nil {
 		usuarioCompleto.Usuario.Nome = *req.Nome
@@ -75,6 +71,11 @@ func UpdateUserData(req UpdateUserDataRequest) (*UpdateUserDataResponse, error)
 		usuarioCompleto.Usuario.Sobrenome = req.Sobrenome
 	}
 	if req.Email != nil {
+		if !strings.EqualFold(*req.Email, usuarioCompleto.Usuario.Email) {
+			if err := RequireFreshToken(claims); err != nil {
+				return nil, err
+			}
+		}
 		usuarioCompleto.Usuario.Email = *req.Email
 	}
 	if req.Gender != nil {
@@ -84,19 +85,10 @@ func UpdateUserData(req UpdateUserDataRequest) (*UpdateUserDataResponse, error)
 		usuarioCompleto.Usuario.DataNascimento = req.DataNascimento
 	}
 
-	// Atualiza campos da tabela usuario_economia
-	if req.Tokens != nil {
-		usuarioCompleto.Economia.Tokens = *req.Tokens
-	}
+	// Atualiza campos da tabela usuario_economia (tokens, battery e plano são só do servidor)
 	if req.Gemas != nil {
 		usuarioCompleto.Economia.Gemas = *req.Gemas
 	}
-	if req.Battery != nil {
-		usuarioCompleto.Economia.Battery = *req.Battery
-	}
-	if req.Plano != nil {
-		usuarioCompleto.Economia.Plano = *req.Plano
-	}
 
 	// Atualiza campos da tabela usuario_progresso
 	if req.LingoEXP != nil {
@@ -160,3 +152,34 @@ func UpdateUserData(req UpdateUserDataRequest) (*UpdateUserDataResponse, error)
 		AccessToken: accessToken,
 	}, nil
 }
+
+// AlterarSenha troca a senha do usuário autenticado. Exige um token fresh (RequireFreshToken)
+// e a senha atual.
+func AlterarSenha(claims *utils.Claims, req AlterarSenhaRequest) error {
+	if err := RequireFreshToken(claims); err != nil {
+		return err
+	}
+	if strings.TrimSpace(req.NovaSenha) == "" {
+		return ErrSenhaVazia
+	}
+
+	usuarioCompleto, err := repositories.GetUsuarioByID(claims.Sub)
+	if err != nil {
+		return errors.New("usuário não encontrado")
+	}
+
+	if !utils.VerifyPassword(req.SenhaAtual, usuarioCompleto.Usuario.Password) {
+		return ErrSenhaIncorreta
+	}
+
+	senhaHash, err := utils.HashPassword(req.NovaSenha)
+	if err != nil {
+		return errors.New("erro ao processar senha")
+	}
+
+	if err := repositories.UpdateSenha(claims.Sub, senhaHash); err != nil {
+		return errors.New("erro ao atualizar senha")
+	}
+
+	return nil
+}
@@ -103,7 +103,7 @@ func CriarUsuario(req CriarUsuarioRequest) error {
 	economia := &models.UsuarioEconomia{
 		Tokens:  0,
 		Gemas:   0,
-		Battery: 10,
+		Battery: models.BatteryMax,
 		Plano:   "free",
 	}
 
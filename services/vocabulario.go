@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lingobotAPI-GO/models"
+	"lingobotAPI-GO/repositories"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Erros do baralho de vocabulário
+var (
+	ErrVocabularioNaoEncontrado = errors.New("palavra não encontrada no vocabulário")
+	ErrVocabularioDuplicado     = errors.New("palavra já está no vocabulário")
+	ErrMensagemNaoEncontrada    = errors.New("mensagem não encontrada")
+	ErrTextoVocabulario         = errors.New("informe text ou mensagem_id")
+	ErrPalavraVazia             = errors.New("palavra é obrigatória")
+)
+
+const (
+	// Parâmetros do SM-2: facilidade mínima e intervalos das duas primeiras revisões
+	sm2FacilidadeMinima  = 1.3
+	sm2PrimeiroIntervalo = 1
+	sm2SegundoIntervalo  = 6
+	// Nota mínima (0-5) para a revisão contar como lembrada
+	sm2NotaAprovacao = 3
+
+	vocabularioRevisaoLimite = 20 // cartões por sessão de revisão
+	vocabularioExtracaoMax   = 10 // palavras pedidas à IA por texto
+	// As traduções vão para o idioma dos alunos do app
+	vocabularioIdiomaTraducao = "Brazilian Portuguese"
+)
+
+// vocabularioSchema é o formato pedido à IA na extração de palavras
+var vocabularioSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []interface{}{"words"},
+	"properties": map[string]interface{}{
+		"words": map[string]interface{}{
+			"type":     "array",
+			"maxItems": vocabularioExtracaoMax,
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"word", "translation", "example"},
+				"properties": map[string]interface{}{
+					"word":        map[string]interface{}{"type": "string", "minLength": 1},
+					"translation": map[string]interface{}{"type": "string"},
+					"example":     map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	},
+}
+
+// AdicionarVocabulario adiciona uma palavra ao baralho no idioma que o usuário estuda (Learning)
+func AdicionarVocabulario(usuarioID int, req models.AdicionarVocabularioRequest) (*models.Vocabulario, error) {
+	palavra := strings.TrimSpace(req.Palavra)
+	if palavra == "" {
+		return nil, ErrPalavraVazia
+	}
+
+	idioma, err := idiomaEstudado(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &models.Vocabulario{
+		UsuarioID: usuarioID,
+		Idioma:    idioma,
+		Palavra:   palavra,
+		Traducao:  req.Traducao,
+		Exemplo:   req.Exemplo,
+		Origem:    models.VocabularioManual,
+	}
+
+	inserido, err := repositories.InsertVocabulario(v)
+	if err != nil {
+		return nil, errors.New("erro ao adicionar palavra")
+	}
+	if !inserido {
+		return nil, ErrVocabularioDuplicado
+	}
+
+	return v, nil
+}
+
+// ExtrairVocabulario pede à IA as palavras que valem estudo em um texto (ou em uma mensagem de
+// conversa do usuário) e adiciona ao baralho as que ainda não estão nele
+func ExtrairVocabulario(ctx context.Context, usuarioID int, req models.ExtrairVocabularioRequest) ([]models.Vocabulario, error) {
+	text := strings.TrimSpace(req.Text)
+	if req.MensagemID > 0 {
+		mensagem, err := repositories.GetMensagemUsuario(usuarioID, req.MensagemID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMensagemNaoEncontrada
+		}
+		if err != nil {
+			return nil, errors.New("erro ao buscar mensagem")
+		}
+		text = mensagem.Content
+	}
+	if text == "" {
+		return nil, ErrTextoVocabulario
+	}
+
+	progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+	if err != nil {
+		return nil, errors.New("erro ao buscar progresso do usuário")
+	}
+	idioma := idiomaDoProgresso(progresso)
+	difficulty := progresso.Difficulty
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+
+	system := fmt.Sprintf(`You are Lingobot, building a %[1]s vocabulary deck for a student at %[2]s difficulty.
+From the text, pick up to %[3]d %[1]s words or short expressions worth studying at that level, skipping names and very basic words.
+Give each word in its dictionary form, its %[4]s translation and a short %[1]s example sentence.`,
+		languageName(idioma), difficulty, vocabularioExtracaoMax, vocabularioIdiomaTraducao)
+
+	response, err := CallAIStructured(ctx, models.CompletionRequest{System: system, Prompt: text}, vocabularioSchema, req.Providers)
+	if err != nil {
+		return nil, err
+	}
+
+	var output struct {
+		Words []struct {
+			Word        string `json:"word"`
+			Translation string `json:"translation"`
+			Example     string `json:"example"`
+		} `json:"words"`
+	}
+	if err := decodeStructured(response.Data, &output); err != nil {
+		return nil, err
+	}
+
+	adicionadas := []models.Vocabulario{}
+	for _, w := range output.Words {
+		palavra := strings.TrimSpace(w.Word)
+		if palavra == "" {
+			continue
+		}
+
+		v := &models.Vocabulario{
+			UsuarioID: usuarioID,
+			Idioma:    idioma,
+			Palavra:   palavra,
+			Origem:    models.VocabularioIA,
+		}
+		if w.Translation != "" {
+			v.Traducao = &w.Translation
+		}
+		if w.Example != "" {
+			v.Exemplo = &w.Example
+		}
+
+		inserido, err := repositories.InsertVocabulario(v)
+		if err != nil {
+			return nil, errors.New("erro ao adicionar palavra")
+		}
+		if inserido {
+			adicionadas = append(adicionadas, *v)
+		}
+	}
+
+	return adicionadas, nil
+}
+
+// ListarVocabulario retorna o baralho do usuário no idioma que ele estuda
+func ListarVocabulario(usuarioID int) ([]models.Vocabulario, error) {
+	idioma, err := idiomaEstudado(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	cartoes, err := repositories.GetVocabularioByUsuario(usuarioID, idioma)
+	if err != nil {
+		return nil, errors.New("erro ao buscar vocabulário")
+	}
+
+	return cartoes, nil
+}
+
+// VocabularioPendente retorna os cartões com revisão vencida no idioma que o usuário estuda
+func VocabularioPendente(usuarioID, limite int) ([]models.Vocabulario, error) {
+	idioma, err := idiomaEstudado(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	if limite <= 0 {
+		limite = vocabularioRevisaoLimite
+	}
+
+	cartoes, err := repositories.GetVocabularioPendente(usuarioID, idioma, limite)
+	if err != nil {
+		return nil, errors.New("erro ao buscar revisões")
+	}
+
+	return cartoes, nil
+}
+
+// RevisarVocabulario aplica a nota de lembrança (0-5) ao cartão e agenda a próxima revisão
+func RevisarVocabulario(usuarioID, vocabularioID, nota int) (*models.Vocabulario, error) {
+	v, err := repositories.GetVocabulario(usuarioID, vocabularioID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVocabularioNaoEncontrado
+	}
+	if err != nil {
+		return nil, errors.New("erro ao buscar palavra")
+	}
+
+	// proxima_revisao é comparada com CURRENT_TIMESTAMP em uma coluna TIMESTAMP (UTC)
+	agendarSM2(v, nota, time.Now().UTC())
+
+	if err := repositories.UpdateRevisaoVocabulario(v); err != nil {
+		return nil, errors.New("erro ao salvar revisão")
+	}
+
+	return v, nil
+}
+
+// ApagarVocabulario remove a palavra do baralho
+func ApagarVocabulario(usuarioID, vocabularioID int) error {
+	apagado, err := repositories.DeleteVocabulario(usuarioID, vocabularioID)
+	if err != nil {
+		return errors.New("erro ao apagar palavra")
+	}
+	if !apagado {
+		return ErrVocabularioNaoEncontrado
+	}
+	return nil
+}
+
+// agendarSM2 aplica o algoritmo SM-2: nota abaixo de 3 recomeça as repetições (revisão amanhã);
+// a partir de 3 o intervalo vai para 1, 6 e depois cresce pela facilidade, que é ajustada pela nota
+func agendarSM2(v *models.Vocabulario, nota int, agora time.Time) {
+	if nota < sm2NotaAprovacao {
+		v.Repeticoes = 0
+		v.Intervalo = sm2PrimeiroIntervalo
+	} else {
+		switch v.Repeticoes {
+		case 0:
+			v.Intervalo = sm2PrimeiroIntervalo
+		case 1:
+			v.Intervalo = sm2SegundoIntervalo
+		default:
+			v.Intervalo = int(math.Round(float64(v.Intervalo) * v.Facilidade))
+		}
+		v.Repeticoes++
+	}
+
+	erro := float64(5 - nota)
+	v.Facilidade = max(v.Facilidade+0.1-erro*(0.08+erro*0.02), sm2FacilidadeMinima)
+	v.ProximaRevisao = agora.AddDate(0, 0, v.Intervalo)
+}
+
+// idiomaEstudado retorna o código do idioma que o usuário estuda (usuario_progresso.learning)
+func idiomaEstudado(usuarioID int) (string, error) {
+	progresso, err := repositories.GetUsuarioProgresso(usuarioID)
+	if err != nil {
+		return "", errors.New("erro ao buscar progresso do usuário")
+	}
+	return idiomaDoProgresso(progresso), nil
+}
+
+// idiomaDoProgresso normaliza o Learning do usuário ("en" quando vazio)
+func idiomaDoProgresso(progresso *models.UsuarioProgresso) string {
+	idioma := strings.ToLower(strings.TrimSpace(progresso.Learning))
+	if idioma == "" {
+		return "en"
+	}
+	return idioma
+}
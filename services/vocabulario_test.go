@@ -0,0 +1,82 @@
+package services
+
+import (
+	"lingobotAPI-GO/models"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAgendarSM2(t *testing.T) {
+	agora := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		cartao         models.Vocabulario
+		nota           int
+		wantIntervalo  int
+		wantRepeticoes int
+		wantFacilidade float64
+	}{
+		{
+			name:           "primeira revisão lembrada",
+			cartao:         models.Vocabulario{Facilidade: 2.5},
+			nota:           5,
+			wantIntervalo:  1,
+			wantRepeticoes: 1,
+			wantFacilidade: 2.6,
+		},
+		{
+			name:           "segunda revisão lembrada",
+			cartao:         models.Vocabulario{Facilidade: 2.5, Intervalo: 1, Repeticoes: 1},
+			nota:           4,
+			wantIntervalo:  6,
+			wantRepeticoes: 2,
+			wantFacilidade: 2.5,
+		},
+		{
+			name:           "intervalo cresce pela facilidade anterior",
+			cartao:         models.Vocabulario{Facilidade: 2.5, Intervalo: 6, Repeticoes: 2},
+			nota:           3,
+			wantIntervalo:  15,
+			wantRepeticoes: 3,
+			wantFacilidade: 2.36,
+		},
+		{
+			name:           "esquecida recomeça as repetições",
+			cartao:         models.Vocabulario{Facilidade: 2.5, Intervalo: 15, Repeticoes: 3},
+			nota:           2,
+			wantIntervalo:  1,
+			wantRepeticoes: 0,
+			wantFacilidade: 2.18,
+		},
+		{
+			name:           "facilidade não passa do mínimo",
+			cartao:         models.Vocabulario{Facilidade: sm2FacilidadeMinima, Intervalo: 6, Repeticoes: 2},
+			nota:           0,
+			wantIntervalo:  1,
+			wantRepeticoes: 0,
+			wantFacilidade: sm2FacilidadeMinima,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.cartao
+			agendarSM2(&v, tt.nota, agora)
+
+			if v.Intervalo != tt.wantIntervalo {
+				t.Errorf("Intervalo = %d, want %d", v.Intervalo, tt.wantIntervalo)
+			}
+			if v.Repeticoes != tt.wantRepeticoes {
+				t.Errorf("Repeticoes = %d, want %d", v.Repeticoes, tt.wantRepeticoes)
+			}
+			if math.Abs(v.Facilidade-tt.wantFacilidade) > 1e-9 {
+				t.Errorf("Facilidade = %v, want %v", v.Facilidade, tt.wantFacilidade)
+			}
+			if want := agora.AddDate(0, 0, tt.wantIntervalo); !v.ProximaRevisao.Equal(want) {
+				t.Errorf("ProximaRevisao = %v, want %v", v.ProximaRevisao, want)
+			}
+		})
+	}
+}
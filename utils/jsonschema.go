@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidateJSONSchema valida um valor (já decodificado com Unmarshal) contra um JSON Schema.
+// Suporta o subconjunto usado nos exercícios: type, properties, required,
+// additionalProperties (booleano), items, enum, minItems/maxItems,
+// minLength/maxLength e minimum/maximum.
+func ValidateJSONSchema(schema map[string]interface{}, value interface{}) error {
+	return validateSchema(schema, value, "$")
+}
+
+func validateSchema(schema map[string]interface{}, value interface{}, path string) error {
+	if t, ok := schema["type"]; ok {
+		if err := validateType(t, value, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: valor %v não está em %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return validateObject(schema, v, path)
+	case []interface{}:
+		return validateArray(schema, v, path)
+	case string:
+		length := len([]rune(v))
+		if min, ok := schemaNumber(schema, "minLength"); ok && float64(length) < min {
+			return fmt.Errorf("%s: texto menor que %v caracteres", path, min)
+		}
+		if max, ok := schemaNumber(schema, "maxLength"); ok && float64(length) > max {
+			return fmt.Errorf("%s: texto maior que %v caracteres", path, max)
+		}
+	case float64:
+		if min, ok := schemaNumber(schema, "minimum"); ok && v < min {
+			return fmt.Errorf("%s: %v menor que o mínimo %v", path, v, min)
+		}
+		if max, ok := schemaNumber(schema, "maximum"); ok && v > max {
+			return fmt.Errorf("%s: %v maior que o máximo %v", path, v, max)
+		}
+	}
+
+	return nil
+}
+
+func validateObject(schema map[string]interface{}, obj map[string]interface{}, path string) error {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, exists := obj[key]; !exists {
+				return fmt.Errorf("%s: campo obrigatório %q ausente", path, key)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Ordena as chaves para que a mensagem de erro seja determinística
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propSchema, ok := properties[key].(map[string]interface{})
+		if !ok {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Errorf("%s: campo %q não permitido", path, key)
+			}
+			continue
+		}
+		if err := validateSchema(propSchema, obj[key], path+"."+key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateArray(schema map[string]interface{}, arr []interface{}, path string) error {
+	if min, ok := schemaNumber(schema, "minItems"); ok && float64(len(arr)) < min {
+		return fmt.Errorf("%s: esperado no mínimo %v itens, recebido %d", path, min, len(arr))
+	}
+	if max, ok := schemaNumber(schema, "maxItems"); ok && float64(len(arr)) > max {
+		return fmt.Errorf("%s: esperado no máximo %v itens, recebido %d", path, max, len(arr))
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range arr {
+			if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateType aceita "type" como string ou lista de strings
+func validateType(t interface{}, value interface{}, path string) error {
+	var allowed []string
+	switch tv := t.(type) {
+	case string:
+		allowed = []string{tv}
+	case []interface{}:
+		for _, item := range tv {
+			if s, ok := item.(string); ok {
+				allowed = append(allowed, s)
+			}
+		}
+	}
+
+	for _, name := range allowed {
+		if matchesType(name, value) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: esperado tipo %s", path, strings.Join(allowed, " ou "))
+}
+
+func matchesType(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	n, ok := schema[key].(float64)
+	if ok {
+		return n, true
+	}
+	if i, ok := schema[key].(int); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
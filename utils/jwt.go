@@ -1,40 +1,48 @@
 package utils
 
 import (
+	"crypto/subtle"
 	"errors"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
-// getJWTSecret obtém a chave secreta do .env
-func getJWTSecret() []byte {
-	secret := os.Getenv("JWT_SECRET_KEY")
-	if secret == "" {
-		panic("JWT_SECRET_KEY não está definida no .env")
-	}
-	return []byte(secret)
-}
+// Duração dos tokens: o access token é curto e renovado pelo /token/refresh
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
 
 // Claims customizado - JWT minimalista com apenas dados essenciais
 type Claims struct {
-	Fresh bool   `json:"fresh"`
-	JTI   string `json:"jti"`
-	Type  string `json:"type"`
-	Sub   int    `json:"sub"` // User ID
-	CSRF  string `json:"csrf"`
+	Fresh  bool   `json:"fresh"`
+	JTI    string `json:"jti"`
+	Type   string `json:"type"`
+	Sub    int    `json:"sub"` // User ID
+	CSRF   string `json:"csrf"`
+	Family string `json:"fam,omitempty"` // família do refresh token (uma por login)
 	jwt.RegisteredClaims
 }
 
-// GenerateAccessToken gera um token de acesso JWT minimalista (7 dias)
+// GenerateAccessToken gera um token de acesso JWT minimalista (AccessTokenTTL)
 func GenerateAccessToken(userID int, userData map[string]interface{}) (string, error) {
+	return generateAccessToken(userID, false)
+}
+
+// GenerateFreshAccessToken gera um access token "fresh", que só o login com senha emite.
+// Operações sensíveis (como trocar o email) exigem um token fresh.
+func GenerateFreshAccessToken(userID int, userData map[string]interface{}) (string, error) {
+	return generateAccessToken(userID, true)
+}
+
+func generateAccessToken(userID int, fresh bool) (string, error) {
 	now := time.Now()
-	exp := now.Add(7 * 24 * time.Hour)
+	exp := now.Add(AccessTokenTTL)
 
 	claims := Claims{
-		Fresh: false,
+		Fresh: fresh,
 		JTI:   uuid.New().String(),
 		Type:  "access",
 		Sub:   userID, // Apenas o ID do usuário
@@ -46,36 +54,44 @@ func GenerateAccessToken(userID int, userData map[string]interface{}) (string, e
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecret())
+	return signToken(claims)
 }
 
-// GenerateRefreshToken gera um token de refresh com duração de 30 dias
-func GenerateRefreshToken(userID int) (string, error) {
+// GenerateRefreshToken gera um token de refresh (RefreshTokenTTL) na família informada.
+// Retorna também os claims para que o JTI e a expiração sejam registrados.
+func GenerateRefreshToken(userID int, family string) (string, *Claims, error) {
 	now := time.Now()
-	exp := now.Add(30 * 24 * time.Hour)
-
-	claims := jwt.MapClaims{
-		"fresh": false,
-		"jti":   uuid.New().String(),
-		"type":  "refresh",
-		"sub":   userID,
-		"csrf":  uuid.New().String(),
-		"exp":   exp.Unix(),
-		"iat":   now.Unix(),
-		"nbf":   now.Unix(),
+	exp := now.Add(RefreshTokenTTL)
+
+	claims := &Claims{
+		Fresh:  false,
+		JTI:    uuid.New().String(),
+		Type:   "refresh",
+		Sub:    userID,
+		CSRF:   uuid.New().String(),
+		Family: family,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(getJWTSecret())
+	signed, err := signToken(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
-// ValidateToken valida e decodifica um token JWT
+// ValidateToken valida e decodifica um token JWT (a chave é escolhida pelo kid do header)
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return getJWTSecret(), nil
-	})
+	keys, err := getJWTKeys()
+	if err != nil {
+		return nil, err
+	}
 
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -86,3 +102,8 @@ func ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("token inválido")
 }
+
+// CSRFMatch confere o valor enviado no header X-CSRF-Token com o claim csrf do token
+func CSRFMatch(header, claim string) bool {
+	return header != "" && claim != "" && subtle.ConstantTimeCompare([]byte(header), []byte(claim)) == 1
+}
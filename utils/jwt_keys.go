@@ -0,0 +1,344 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Tamanho mínimo aceito para chaves RSA
+const jwtRSAMinBits = 2048
+
+// jwtKey é uma chave de assinatura/verificação de JWT identificada pelo kid
+type jwtKey struct {
+	kid    string
+	method jwt.SigningMethod
+	sign   interface{} // chave privada (ou segredo HS256); nil nas chaves só de verificação
+	verify interface{} // chave pública (ou segredo HS256)
+}
+
+// jwtKeySet reúne a chave que assina e as chaves aceitas na verificação (rotação)
+type jwtKeySet struct {
+	signing *jwtKey
+	byKID   map[string]*jwtKey
+	legacy  *jwtKey // HS256 sem kid: assina sem chave privada; com ela, só até legacyUntil
+	// Com chave privada, tokens HS256 antigos valem até aqui (zero = não valem mais)
+	legacyUntil time.Time
+}
+
+// JSONWebKey é uma chave pública no formato JWK (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`   // RSA: módulo
+	E   string `json:"e,omitempty"`   // RSA: expoente
+	Crv string `json:"crv,omitempty"` // OKP: curva (Ed25519)
+	X   string `json:"x,omitempty"`   // OKP: chave pública
+}
+
+// JSONWebKeySet é o documento servido em /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+var (
+	jwtKeysOnce sync.Once
+	jwtKeys     *jwtKeySet
+	jwtKeysErr  error
+)
+
+// LoadJWTKeys carrega e valida as chaves do JWT. Deve ser chamada na inicialização (depois
+// do .env) para que uma configuração inválida impeça o servidor de subir.
+//
+//   - JWT_PRIVATE_KEY_FILE (ou JWT_PRIVATE_KEY, PEM): chave RSA (RS256) ou Ed25519 (EdDSA) que assina
+//   - JWT_PUBLIC_KEY_FILES: PEMs separados por vírgula com chaves públicas antigas, ainda aceitas
+//   - JWT_SECRET_KEY: sem chave privada, assina em HS256
+//   - JWT_LEGACY_ACCEPT_UNTIL (RFC 3339): com chave privada, até quando tokens HS256 antigos (sem kid)
+//     ainda valem; sem ela, o segredo deixa de valer assim que a chave privada é configurada
+func LoadJWTKeys() error {
+	_, err := getJWTKeys()
+	return err
+}
+
+func getJWTKeys() (*jwtKeySet, error) {
+	jwtKeysOnce.Do(func() {
+		jwtKeys, jwtKeysErr = loadJWTKeySet()
+	})
+	return jwtKeys, jwtKeysErr
+}
+
+func loadJWTKeySet() (*jwtKeySet, error) {
+	set := &jwtKeySet{byKID: map[string]*jwtKey{}}
+
+	if secret := os.Getenv("JWT_SECRET_KEY"); secret != "" {
+		set.legacy = &jwtKey{method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)}
+	}
+
+	privatePEM, source, err := readPrivateKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	if privatePEM == nil {
+		if set.legacy == nil {
+			return nil, errors.New("defina JWT_PRIVATE_KEY_FILE (RS256/EdDSA) ou JWT_SECRET_KEY (HS256)")
+		}
+		set.signing = set.legacy
+		return set, nil
+	}
+
+	signing, err := parsePrivateKeyPEM(privatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	set.signing = signing
+	set.byKID[signing.kid] = signing
+
+	if value := os.Getenv("JWT_LEGACY_ACCEPT_UNTIL"); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_LEGACY_ACCEPT_UNTIL inválido (use RFC 3339): %v", err)
+		}
+		if set.legacy == nil {
+			return nil, errors.New("JWT_LEGACY_ACCEPT_UNTIL exige o JWT_SECRET_KEY antigo")
+		}
+		set.legacyUntil = until
+	}
+
+	for _, path := range strings.Split(os.Getenv("JWT_PUBLIC_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		keys, err := readPublicKeysFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PUBLIC_KEY_FILES %s: %v", path, err)
+		}
+		for _, key := range keys {
+			if _, exists := set.byKID[key.kid]; !exists {
+				set.byKID[key.kid] = key
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// readPrivateKeyPEM lê a chave privada do arquivo ou da variável (nil se nenhuma foi definida)
+func readPrivateKeyPEM() ([]byte, string, error) {
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("erro ao ler JWT_PRIVATE_KEY_FILE: %v", err)
+		}
+		return data, "JWT_PRIVATE_KEY_FILE", nil
+	}
+	if value := os.Getenv("JWT_PRIVATE_KEY"); value != "" {
+		// Permite a chave em uma linha só, com \n literais
+		return []byte(strings.ReplaceAll(value, `\n`, "\n")), "JWT_PRIVATE_KEY", nil
+	}
+	return nil, "", nil
+}
+
+func parsePrivateKeyPEM(data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("chave privada não está em PEM")
+	}
+
+	var private interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("bloco PEM %q não suportado (use PKCS#8)", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler chave privada: %v", err)
+	}
+
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		key, err := newJWTKey(&k.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		key.sign = k
+		return key, nil
+	case ed25519.PrivateKey:
+		key, err := newJWTKey(k.Public())
+		if err != nil {
+			return nil, err
+		}
+		key.sign = k
+		return key, nil
+	default:
+		return nil, fmt.Errorf("tipo de chave %T não suportado (use RSA ou Ed25519)", private)
+	}
+}
+
+// readPublicKeysFile lê todas as chaves públicas de um arquivo PEM (chaves privadas também
+// são aceitas; só a parte pública é usada)
+func readPublicKeysFile(path string) ([]*jwtKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*jwtKey{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var public interface{}
+		switch block.Type {
+		case "PUBLIC KEY":
+			public, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "PRIVATE KEY", "RSA PRIVATE KEY":
+			var key *jwtKey
+			if key, err = parsePrivateKeyPEM(pem.EncodeToMemory(block)); err == nil {
+				public = key.verify
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler chave pública: %v", err)
+		}
+
+		key, err := newJWTKey(public)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("nenhuma chave pública encontrada")
+	}
+	return keys, nil
+}
+
+// newJWTKey monta a chave de verificação, com o kid igual ao thumbprint do JWK (RFC 7638)
+func newJWTKey(public interface{}) (*jwtKey, error) {
+	key := &jwtKey{verify: public}
+
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < jwtRSAMinBits {
+			return nil, fmt.Errorf("chave RSA de %d bits: o mínimo é %d", k.N.BitLen(), jwtRSAMinBits)
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("tipo de chave %T não suportado (use RSA ou Ed25519)", public)
+	}
+
+	jwk := key.jwk()
+	var thumbprint string
+	if jwk.Kty == "RSA" {
+		thumbprint = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	} else {
+		thumbprint = fmt.Sprintf(`{"crv":"Ed25519","kty":"OKP","x":%q}`, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(thumbprint))
+	key.kid = base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return key, nil
+}
+
+// jwk retorna a parte pública da chave no formato JWK
+func (k *jwtKey) jwk() JSONWebKey {
+	jwk := JSONWebKey{Use: "sig", Alg: k.method.Alg(), Kid: k.kid}
+
+	switch public := k.verify.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// keyFunc escolhe a chave de verificação pelo kid e recusa tokens cujo alg não é o da chave
+func (s *jwtKeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	key := s.legacy
+	if kid, ok := token.Header["kid"].(string); ok {
+		key = s.byKID[kid]
+	} else if key != nil && key != s.signing && !time.Now().Before(s.legacyUntil) {
+		return nil, errors.New("tokens HS256 sem kid não são mais aceitos")
+	}
+	if key == nil {
+		return nil, errors.New("chave do token desconhecida")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("algoritmo do token não confere com a chave")
+	}
+	return key.verify, nil
+}
+
+// signToken assina os claims com a chave atual, informando o kid no header
+func signToken(claims jwt.Claims) (string, error) {
+	keys, err := getJWTKeys()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(keys.signing.method, claims)
+	if keys.signing.kid != "" {
+		token.Header["kid"] = keys.signing.kid
+	}
+	return token.SignedString(keys.signing.sign)
+}
+
+// JWKS retorna as chaves públicas aceitas na verificação (vazio no modo HS256)
+func JWKS() (*JSONWebKeySet, error) {
+	keys, err := getJWTKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	// A chave que assina vem primeiro; as antigas em ordem de kid
+	set := &JSONWebKeySet{Keys: []JSONWebKey{}}
+	if keys.signing.kid != "" {
+		set.Keys = append(set.Keys, keys.signing.jwk())
+	}
+	kids := make([]string, 0, len(keys.byKID))
+	for kid := range keys.byKID {
+		if kid != keys.signing.kid {
+			kids = append(kids, kid)
+		}
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		set.Keys = append(set.Keys, keys.byKID[kid].jwk())
+	}
+	return set, nil
+}